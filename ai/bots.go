@@ -0,0 +1,25 @@
+package ai
+
+// SummarizeBots condenses automated commits into one entry per bot without
+// involving the model.
+func SummarizeBots(commits []Commit) []BotActivity {
+	index := map[string]int{}
+	var out []BotActivity
+	var own [][]Commit
+	for _, c := range commits {
+		key := c.Handle()
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, BotActivity{Name: key})
+			own = append(own, nil)
+		}
+		out[i].Commits++
+		own[i] = append(own[i], c)
+	}
+	for i := range out {
+		out[i].Changes = dedupeSubjects(own[i])
+	}
+	return out
+}
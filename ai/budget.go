@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/urizennnn/autostandup-reposcanner/config"
+)
+
+const (
+	BudgetDowngraded = "downgraded"
+	BudgetHeuristic  = "heuristic"
+
+	// estimatedCompletionTokens is a deliberately generous guess at the size of
+	// a standup payload; it only feeds the pre-flight budget check.
+	estimatedCompletionTokens = 2000
+)
+
+type Budget struct {
+	rdb               *redis.Client
+	pricing           config.PricingTable
+	installationDaily float64
+	globalDaily       float64
+}
+
+type BudgetPlan struct {
+	Model    string
+	Decision string
+}
+
+func NewBudget(rdb *redis.Client, pricing config.PricingTable, installationDaily, globalDaily float64) *Budget {
+	return &Budget{
+		rdb:               rdb,
+		pricing:           pricing,
+		installationDaily: installationDaily,
+		globalDaily:       globalDaily,
+	}
+}
+
+// Plan picks the first of primary, fallback and the heuristic summarizer whose
+// estimated cost still fits the installation and global daily budgets.
+func (b *Budget) Plan(ctx context.Context, installationID int64, job SummarizeJob, format FormatType, primary, fallback string) (BudgetPlan, error) {
+	if b.installationDaily <= 0 && b.globalDaily <= 0 {
+		return BudgetPlan{Model: primary}, nil
+	}
+
+	spentInstallation, spentGlobal, err := b.spent(ctx, installationID)
+	if err != nil {
+		return BudgetPlan{Model: primary}, err
+	}
+
+	fits := func(model string) bool {
+		cost := b.Estimate(model, job, format)
+		if b.installationDaily > 0 && spentInstallation+cost > b.installationDaily {
+			return false
+		}
+		if b.globalDaily > 0 && spentGlobal+cost > b.globalDaily {
+			return false
+		}
+		return true
+	}
+
+	switch {
+	case fits(primary):
+		return BudgetPlan{Model: primary}, nil
+	case fallback != "" && fallback != primary && fits(fallback):
+		return BudgetPlan{Model: fallback, Decision: BudgetDowngraded}, nil
+	default:
+		return BudgetPlan{Model: HeuristicModel, Decision: BudgetHeuristic}, nil
+	}
+}
+
+func (b *Budget) Estimate(model string, job SummarizeJob, format FormatType) float64 {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return 0
+	}
+	// Roughly four characters per token for English prose and JSON.
+	promptTokens := int64(len(getSystemPrompt(job, format))+len(jobJSON)) / 4
+	return b.pricing.Cost(ProviderOpenAI, model, promptTokens, 0, estimatedCompletionTokens)
+}
+
+func (b *Budget) Record(ctx context.Context, installationID int64, cost float64) error {
+	if cost <= 0 {
+		return nil
+	}
+	day := time.Now().UTC().Format("2006-01-02")
+	pipe := b.rdb.TxPipeline()
+	for _, key := range []string{installationKey(day, installationID), globalKey(day)} {
+		pipe.IncrByFloat(ctx, key, cost)
+		pipe.Expire(ctx, key, 48*time.Hour)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("recording spend: %w", err)
+	}
+	return nil
+}
+
+func (b *Budget) spent(ctx context.Context, installationID int64) (float64, float64, error) {
+	day := time.Now().UTC().Format("2006-01-02")
+	vals, err := b.rdb.MGet(ctx, installationKey(day, installationID), globalKey(day)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, fmt.Errorf("reading spend: %w", err)
+	}
+
+	out := make([]float64, 2)
+	for i, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parsing spend: %w", err)
+		}
+		out[i] = f
+	}
+	return out[0], out[1], nil
+}
+
+func installationKey(day string, installationID int64) string {
+	return fmt.Sprintf("budget:%s:installation:%d", day, installationID)
+}
+
+func globalKey(day string) string {
+	return "budget:" + day + ":global"
+}
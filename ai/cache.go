@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type ResultCache struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewResultCache(rdb *redis.Client, ttl time.Duration) *ResultCache {
+	return &ResultCache{rdb: rdb, ttl: ttl}
+}
+
+// SummaryKey identifies a summary by everything that can change the model's
+// answer: the commit set with its branches and diffs, pull request and issue
+// activity, CI state, releases, the component and file breakdowns, the
+// format, the language, the mode, the prompt version and the model.
+func SummaryKey(job SummarizeJob, format FormatType, model string) string {
+	shas := make([]string, 0, len(job.Commits))
+	for _, c := range job.Commits {
+		// Branch membership changes what counts as shipped versus in
+		// progress, so it is part of the commit's entry.
+		if c.Branches != nil {
+			shas = append(shas, c.SHA+" "+strings.Join(c.Branches, ","))
+			continue
+		}
+		shas = append(shas, c.SHA)
+	}
+	sort.Strings(shas)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "repo=%s\nformat=%s:%s\nlanguage=%s\nmode=%s\nprompt=%s\nschema=%s\nmodel=%s\n",
+		job.Repo, format, formatFingerprint(format), job.Language, job.Mode, PromptVersion, SchemaVersion, model)
+	for _, sha := range shas {
+		fmt.Fprintln(h, sha)
+	}
+	// Diff excerpts depend on the byte budget and skip rules in force.
+	for _, c := range job.Commits {
+		if len(c.Diff) > 0 {
+			b, _ := json.Marshal(c.Diff)
+			h.Write(b)
+		}
+	}
+	// Pull request and issue activity changes the summary even when the
+	// commits do not.
+	if len(job.PullRequests) > 0 {
+		b, _ := json.Marshal(job.PullRequests)
+		h.Write(b)
+	}
+	if len(job.Issues) > 0 {
+		b, _ := json.Marshal(job.Issues)
+		h.Write(b)
+	}
+	if job.CI != nil {
+		b, _ := json.Marshal(job.CI)
+		h.Write(b)
+	}
+	if len(job.Releases) > 0 {
+		b, _ := json.Marshal(job.Releases)
+		h.Write(b)
+	}
+	// Path filters change the per-commit stats without changing the SHAs;
+	// the component breakdown reflects them.
+	if len(job.Components) > 0 {
+		b, _ := json.Marshal(job.Components)
+		h.Write(b)
+	}
+	if job.FilesChanged != nil {
+		b, _ := json.Marshal(job.FilesChanged)
+		h.Write(b)
+	}
+	return "summary:" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *ResultCache) Get(ctx context.Context, key string) (SummarizeResult, bool, error) {
+	b, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return SummarizeResult{}, false, nil
+	}
+	if err != nil {
+		return SummarizeResult{}, false, fmt.Errorf("reading summary cache: %w", err)
+	}
+
+	var res SummarizeResult
+	if err := json.Unmarshal(b, &res); err != nil {
+		return SummarizeResult{}, false, fmt.Errorf("decoding cached summary: %w", err)
+	}
+
+	// The tokens were paid for by the run that populated the cache.
+	res.Details.PromptTokens = 0
+	res.Details.CachedPromptTokens = 0
+	res.Details.CompletionTokens = 0
+	res.Details.TotalTokens = 0
+	res.Details.EstimatedCost = 0
+	res.Details.CacheHit = true
+	return res, true, nil
+}
+
+func (c *ResultCache) Set(ctx context.Context, key string, res SummarizeResult) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("encoding summary: %w", err)
+	}
+	if err := c.rdb.Set(ctx, key, b, c.ttl).Err(); err != nil {
+		return fmt.Errorf("writing summary cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ModeRepo           = "repo"
+	ModePerContributor = "per_contributor"
+)
+
+func ResolveMode(mode string) (string, error) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(mode), "-", "_")) {
+	case "", ModeRepo:
+		return ModeRepo, nil
+	case ModePerContributor:
+		return ModePerContributor, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q", mode)
+	}
+}
+
+// Handle is the identity contributions are grouped under: the GitHub login
+// when known, otherwise the email or name.
+func (p Person) Handle() string {
+	switch {
+	case p.Login != "":
+		return p.Login
+	case p.Email != "":
+		return strings.ToLower(p.Email)
+	default:
+		return p.Name
+	}
+}
+
+func (p Person) matches(handle string) bool {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	return strings.EqualFold(p.Login, handle) ||
+		strings.EqualFold(p.Email, handle) ||
+		strings.EqualFold(p.Name, handle)
+}
+
+func (c Commit) Author() Person {
+	return Person{Name: c.AuthorName, Email: c.AuthorEmail, Login: c.AuthorLogin}
+}
+
+func (c Commit) Handle() string {
+	return c.Author().Handle()
+}
+
+// credited lists everyone who gets credit for the commit: the author first,
+// then each distinct co-author.
+func (c Commit) credited() []Person {
+	out := []Person{c.Author()}
+	seen := map[string]bool{c.Handle(): true}
+	for _, p := range c.CoAuthors {
+		if seen[p.Handle()] {
+			continue
+		}
+		seen[p.Handle()] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+func FilterByContributor(commits []Commit, handle string) []Commit {
+	if strings.TrimSpace(handle) == "" {
+		return commits
+	}
+	out := make([]Commit, 0, len(commits))
+	for _, c := range commits {
+		for _, p := range c.credited() {
+			if p.matches(handle) {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// FilterPullRequests keeps pull requests the handle authored or reviewed.
+func FilterPullRequests(prs []PullRequest, handle string) []PullRequest {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if handle == "" {
+		return prs
+	}
+	out := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		keep := strings.EqualFold(pr.Author, handle)
+		for _, r := range pr.Reviews {
+			keep = keep || strings.EqualFold(r.Reviewer, handle)
+		}
+		if keep {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// GroupByContributor aggregates commits per identity, in order of first
+// appearance. Co-authors are credited with the full commit, so pairs both see
+// the work in their stats.
+func GroupByContributor(commits []Commit) []ContributorActivity {
+	index := map[string]int{}
+	var out []ContributorActivity
+	var credited [][]Commit
+	for _, c := range commits {
+		for n, p := range c.credited() {
+			key := p.Handle()
+			i, ok := index[key]
+			if !ok {
+				i = len(out)
+				index[key] = i
+				out = append(out, ContributorActivity{Handle: key, Name: p.Name, Email: p.Email, Login: p.Login})
+				credited = append(credited, nil)
+			}
+			out[i].Commits++
+			if n > 0 {
+				out[i].CoAuthored++
+			}
+			out[i].SHAs = append(out[i].SHAs, c.SHA)
+			credited[i] = append(credited[i], c)
+		}
+	}
+	for i := range out {
+		out[i].FilesChanged = SummarizeFiles(credited[i])
+	}
+	return out
+}
+
+// FilterActivity keeps the activity of the contributor matching handle. A
+// contributor-filtered job still has commits co-authored with others, whose
+// grouping would otherwise give each of those partners a partial section.
+func FilterActivity(activity []ContributorActivity, handle string) []ContributorActivity {
+	if strings.TrimSpace(handle) == "" {
+		return activity
+	}
+	out := make([]ContributorActivity, 0, 1)
+	for _, a := range activity {
+		if (Person{Name: a.Name, Email: a.Email, Login: a.Login}).matches(handle) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func BuildContributors(commits []Commit) []Contributor {
+	activity := GroupByContributor(commits)
+	out := make([]Contributor, 0, len(activity))
+	for _, a := range activity {
+		out = append(out, Contributor{
+			Name:       a.Name,
+			Email:      a.Email,
+			Login:      a.Login,
+			Commits:    a.Commits,
+			CoAuthored: a.CoAuthored,
+		})
+	}
+	return out
+}
+
+// fillSections replaces the model's view of who did what with the computed
+// stats, keeping only the prose it wrote for each handle.
+func fillSections(out *StandupPayload, job SummarizeJob) {
+	written := map[string]ContributorSection{}
+	for _, s := range out.Sections {
+		written[strings.ToLower(s.Handle)] = s
+	}
+
+	sections := make([]ContributorSection, 0, len(job.Contributors))
+	for _, a := range job.Contributors {
+		s := written[strings.ToLower(a.Handle)]
+		s.Handle = a.Handle
+		s.Name = a.Name
+		s.Email = a.Email
+		s.CommitCount = a.Commits
+		s.CoAuthored = a.CoAuthored
+		s.FilesChanged = a.FilesChanged
+		s.WhatWorkedOn = pruneEmpty(s.WhatWorkedOn)
+		s.Commits = pruneEmpty(s.Commits)
+		sections = append(sections, s)
+	}
+	out.Sections = sections
+}
@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	CategorySource = "source"
+	CategoryTest   = "test"
+	CategoryDocs   = "docs"
+	CategoryConfig = "config"
+)
+
+// otherLanguage groups files whose extension is not recognised.
+const otherLanguage = "Other"
+
+var extensionLanguages = map[string]string{
+	".go":     "Go",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".mts":    "TypeScript",
+	".js":     "JavaScript",
+	".jsx":    "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".py":     "Python",
+	".rb":     "Ruby",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".swift":  "Swift",
+	".rs":     "Rust",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".php":    "PHP",
+	".scala":  "Scala",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".lua":    "Lua",
+	".r":      "R",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".sql":    "SQL",
+	".html":   "HTML",
+	".css":    "CSS",
+	".scss":   "CSS",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".proto":  "Protocol Buffers",
+	".tf":     "HCL",
+	".md":     "Markdown",
+	".rst":    "reStructuredText",
+	".yml":    "YAML",
+	".yaml":   "YAML",
+	".json":   "JSON",
+	".toml":   "TOML",
+	".xml":    "XML",
+}
+
+var filenameLanguages = map[string]string{
+	"dockerfile":  "Dockerfile",
+	"makefile":    "Makefile",
+	"go.mod":      "Go",
+	"go.sum":      "Go",
+	"jenkinsfile": "Groovy",
+}
+
+var docsExtensions = map[string]bool{".md": true, ".mdx": true, ".rst": true, ".adoc": true, ".txt": true}
+
+var configExtensions = map[string]bool{
+	".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true,
+	".cfg": true, ".conf": true, ".env": true, ".properties": true, ".lock": true,
+}
+
+var configFilenames = map[string]bool{
+	"dockerfile": true, "makefile": true, "go.mod": true, "go.sum": true,
+	"package.json": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	".gitignore": true, ".dockerignore": true, ".editorconfig": true, "codeowners": true,
+}
+
+// FileLanguage names the language of p from its extension or well-known
+// file name, or "Other".
+func FileLanguage(p string) string {
+	base := strings.ToLower(path.Base(p))
+	if lang, ok := filenameLanguages[base]; ok {
+		return lang
+	}
+	if lang, ok := extensionLanguages[path.Ext(base)]; ok {
+		return lang
+	}
+	return otherLanguage
+}
+
+// FileCategory classifies p as test, docs, config or source from its path
+// alone.
+func FileCategory(p string) string {
+	lower := strings.ToLower(p)
+	base := path.Base(lower)
+	ext := path.Ext(base)
+	dirs := "/" + path.Dir(lower) + "/"
+
+	switch {
+	case strings.Contains(base, "_test.") || strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(base, "test_") ||
+		strings.Contains(dirs, "/test/") || strings.Contains(dirs, "/tests/") || strings.Contains(dirs, "/__tests__/") ||
+		strings.Contains(dirs, "/testdata/"):
+		return CategoryTest
+	case docsExtensions[ext] || strings.Contains(dirs, "/docs/") || strings.HasPrefix(base, "license") ||
+		strings.HasPrefix(base, "readme") || strings.HasPrefix(base, "changelog"):
+		return CategoryDocs
+	case configExtensions[ext] || configFilenames[base] || strings.HasPrefix(lower, ".github/"):
+		return CategoryConfig
+	default:
+		return CategorySource
+	}
+}
+
+// SummarizeFiles totals the commits' changes and breaks them down by
+// language and category, largest first.
+func SummarizeFiles(commits []Commit) FilesChanged {
+	var out FilesChanged
+	languages := map[string]*ChangeShare{}
+	categories := map[string]*ChangeShare{}
+	for _, c := range commits {
+		out.Files += c.Files
+		out.Additions += c.Additions
+		out.Deletions += c.Deletions
+		for _, f := range c.FileChanges {
+			addShare(languages, f.Language, f)
+			addShare(categories, f.Category, f)
+		}
+	}
+	out.Languages = sortedShares(languages)
+	out.Categories = sortedShares(categories)
+	return out
+}
+
+func addShare(shares map[string]*ChangeShare, name string, f FileChange) {
+	if name == "" {
+		return
+	}
+	s, ok := shares[name]
+	if !ok {
+		s = &ChangeShare{Name: name}
+		shares[name] = s
+	}
+	s.Files++
+	s.Additions += f.Additions
+	s.Deletions += f.Deletions
+}
+
+func sortedShares(shares map[string]*ChangeShare) []ChangeShare {
+	out := make([]ChangeShare, 0, len(shares))
+	for _, s := range shares {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Additions+out[i].Deletions, out[j].Additions+out[j].Deletions
+		if a != b {
+			return a > b
+		}
+		return out[i].Name < out[j].Name
+	})
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,244 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatSpec describes a team-defined output format. The model fills the
+// payload's "custom" object using Schema; Prompt replaces the built-in level
+// instructions.
+type FormatSpec struct {
+	Name     FormatType     `json:"name"`
+	Prompt   string         `json:"prompt"`
+	Schema   map[string]any `json:"schema"`
+	Prune    PruneRules     `json:"prune"`
+	Fallback FormatType     `json:"fallback"`
+}
+
+type PruneRules struct {
+	DropEmpty bool     `json:"dropEmpty"`
+	MaxItems  int      `json:"maxItems"`
+	Omit      []string `json:"omit"`
+}
+
+var formatNameRe = regexp.MustCompile(`^[a-z0-9_]{1,40}$`)
+
+var (
+	formatsMu   sync.RWMutex
+	customSpecs = map[FormatType]FormatSpec{}
+)
+
+func NormalizeFormat(name string) FormatType {
+	return FormatType(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "-", "_")))
+}
+
+func isBuiltinFormat(format FormatType) bool {
+	switch format {
+	case FormatTechnical, FormatMildlyTechnical, FormatLayman:
+		return true
+	}
+	return false
+}
+
+// ResolveFormat maps a job's format string to a known format. An empty string
+// selects the technical format; anything unrecognised is an error.
+func ResolveFormat(name string) (FormatType, error) {
+	format := NormalizeFormat(name)
+	if format == "" {
+		return FormatTechnical, nil
+	}
+	if isBuiltinFormat(format) {
+		return format, nil
+	}
+	if _, ok := lookupCustomFormat(format); ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+func lookupCustomFormat(format FormatType) (FormatSpec, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	spec, ok := customSpecs[format]
+	return spec, ok
+}
+
+// LoadFormats registers every *.json format definition in dir. An empty dir
+// leaves only the built-in formats available.
+func LoadFormats(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("listing formats: %w", err)
+	}
+	sort.Strings(paths)
+
+	specs := map[FormatType]FormatSpec{}
+	for _, p := range paths {
+		spec, err := loadFormatFile(p)
+		if err != nil {
+			return err
+		}
+		if _, dup := specs[spec.Name]; dup {
+			return fmt.Errorf("format %s: defined more than once", spec.Name)
+		}
+		specs[spec.Name] = spec
+	}
+
+	formatsMu.Lock()
+	customSpecs = specs
+	formatsMu.Unlock()
+
+	log.Printf("[INFO] loaded %d custom format(s) from %s", len(specs), dir)
+	return nil
+}
+
+func loadFormatFile(path string) (FormatSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return FormatSpec{}, fmt.Errorf("reading format %s: %w", path, err)
+	}
+
+	var spec FormatSpec
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return FormatSpec{}, fmt.Errorf("parsing format %s: %w", path, err)
+	}
+
+	if spec.Name == "" {
+		spec.Name = FormatType(strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	spec.Name = NormalizeFormat(string(spec.Name))
+	if !formatNameRe.MatchString(string(spec.Name)) {
+		return FormatSpec{}, fmt.Errorf("format %q: name must be 1-40 letters, digits, hyphens or underscores", spec.Name)
+	}
+	if isBuiltinFormat(spec.Name) {
+		return FormatSpec{}, fmt.Errorf("format %s: cannot redefine a built-in format", spec.Name)
+	}
+	if strings.TrimSpace(spec.Prompt) == "" {
+		return FormatSpec{}, fmt.Errorf("format %s: prompt is required", spec.Name)
+	}
+	if spec.Schema == nil || spec.Schema["type"] != "object" {
+		return FormatSpec{}, fmt.Errorf("format %s: schema must be an object schema", spec.Name)
+	}
+	if err := strictify(spec.Schema); err != nil {
+		return FormatSpec{}, fmt.Errorf("format %s: %w", spec.Name, err)
+	}
+
+	if spec.Fallback == "" {
+		spec.Fallback = FormatMildlyTechnical
+	}
+	spec.Fallback = NormalizeFormat(string(spec.Fallback))
+	if !isBuiltinFormat(spec.Fallback) {
+		return FormatSpec{}, fmt.Errorf("format %s: fallback must be a built-in format", spec.Name)
+	}
+
+	return spec, nil
+}
+
+// strictify rewrites a schema fragment in place so structured outputs accept
+// it: objects close their properties and require all of them.
+func strictify(schema map[string]any) error {
+	switch schema["type"] {
+	case "object":
+		props, ok := schema["properties"].(map[string]any)
+		if !ok || len(props) == 0 {
+			return fmt.Errorf("object schema needs properties")
+		}
+		required := make([]string, 0, len(props))
+		for name, p := range props {
+			child, ok := p.(map[string]any)
+			if !ok {
+				return fmt.Errorf("property %q is not a schema", name)
+			}
+			if err := strictify(child); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			required = append(required, name)
+		}
+		sort.Strings(required)
+		schema["required"] = required
+		schema["additionalProperties"] = false
+	case "array":
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("array schema needs items")
+		}
+		return strictify(items)
+	case "string", "integer", "number", "boolean":
+	default:
+		return fmt.Errorf("unsupported schema type %v", schema["type"])
+	}
+	return nil
+}
+
+// formatFingerprint changes whenever a custom format's definition does, so
+// cached summaries from an older definition are not reused.
+func formatFingerprint(format FormatType) string {
+	spec, ok := lookupCustomFormat(format)
+	if !ok {
+		return ""
+	}
+	b, _ := json.Marshal(spec)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func pruneCustom(v any, rules PruneRules) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for _, k := range rules.Omit {
+			delete(t, k)
+		}
+		for k, child := range t {
+			child = pruneCustom(child, PruneRules{DropEmpty: rules.DropEmpty, MaxItems: rules.MaxItems})
+			if rules.DropEmpty && isEmptyValue(child) {
+				delete(t, k)
+				continue
+			}
+			t[k] = child
+		}
+		return t
+	case []any:
+		out := make([]any, 0, len(t))
+		for _, child := range t {
+			child = pruneCustom(child, PruneRules{DropEmpty: rules.DropEmpty, MaxItems: rules.MaxItems})
+			if rules.DropEmpty && isEmptyValue(child) {
+				continue
+			}
+			out = append(out, child)
+		}
+		if rules.MaxItems > 0 && len(out) > rules.MaxItems {
+			out = out[:rules.MaxItems]
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(t) == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
@@ -0,0 +1,402 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/config"
+)
+
+const HeuristicModel = "heuristic"
+
+var (
+	conventionalRe = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	prRefRe        = regexp.MustCompile(`\s*\(#\d+\)$`)
+	nonWordRe      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+var commitTypeLabels = map[string]string{
+	"feat":     "Features",
+	"fix":      "Fixes",
+	"perf":     "Performance",
+	"refactor": "Refactoring",
+	"docs":     "Documentation",
+	"test":     "Tests",
+	"build":    "Build",
+	"ci":       "CI",
+	"chore":    "Chores",
+	"style":    "Style",
+	"revert":   "Reverts",
+	"other":    "Other changes",
+}
+
+var commitTypePlain = map[string]string{
+	"feat":     "Added new functionality",
+	"fix":      "Fixed problems",
+	"perf":     "Made things faster",
+	"refactor": "Cleaned up existing code",
+	"docs":     "Updated documentation",
+	"test":     "Improved testing",
+	"build":    "Updated the build setup",
+	"ci":       "Updated automated checks",
+	"chore":    "Did maintenance work",
+	"style":    "Tidied formatting",
+	"revert":   "Rolled back changes",
+	"other":    "Made other changes",
+}
+
+type commitGroup struct {
+	Type     string
+	Scope    string
+	Subjects []string
+}
+
+// SummarizeWithFallback calls Summarize and, when the provider cannot produce a
+// summary, falls back to SummarizeHeuristic so the standup still goes out.
+func SummarizeWithFallback(ctx context.Context, apiKey, model string, pricing config.PricingTable, job SummarizeJob, format FormatType) (SummarizeResult, error) {
+	res, err := Summarize(ctx, apiKey, model, pricing, job, format)
+	if err == nil {
+		return res, nil
+	}
+	if !shouldFallback(ctx, err) {
+		return SummarizeResult{}, err
+	}
+	log.Printf("[WARN] ai summarize failed repo=%s, using heuristic summary: %v", job.Repo, err)
+	return SummarizeHeuristic(job, format), nil
+}
+
+// shouldFallback reports whether err is final for this job. The OpenAI client
+// already retries 408/409/429/5xx and connection errors, so anything surfacing
+// here is either non-retryable or has exhausted its retries. Only a cancelled
+// job context is passed through, since there is nobody left to publish for.
+func shouldFallback(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil
+}
+
+func SummarizeHeuristic(job SummarizeJob, format FormatType) SummarizeResult {
+	// Custom schemas cannot be filled from templates, so they degrade to the
+	// built-in level their definition names.
+	if spec, ok := lookupCustomFormat(format); ok {
+		format = spec.Fallback
+	}
+
+	var out StandupPayload
+	out.Repo = job.Repo
+	out.Title = fmt.Sprintf("Standup for %s – %s", job.ProjectName, job.Until.Format("Jan 2, 2006"))
+	out.Window.Since = job.Since.Format("Jan 2, 2006")
+	out.Window.Until = job.Until.Format("Jan 2, 2006")
+	out.Contributors = BuildContributors(job.Commits)
+	out.Releases = job.Releases
+	out.Components = job.Components
+
+	header := fmt.Sprintf("📊 **Daily Standup for @%s** – %s", job.Handle, job.ProjectName)
+	shipped, wip := splitInProgress(job.Commits)
+	groups := groupCommits(shipped)
+
+	files := SummarizeFiles(job.Commits)
+
+	prBullets := append(releaseBullets(job.Releases), pullRequestBullets(job.PullRequests)...)
+	prBullets = append(prBullets, issueBullets(job.Issues)...)
+	prBullets = append(prBullets, inProgressBullets(wip)...)
+
+	switch format {
+	case FormatTechnical:
+		out.Technical = TechnicalLevel{
+			Header:       header,
+			WhatWorkedOn: append(append(ciBullets(job.CI), technicalBullets(groups)...), prBullets...),
+			FilesChanged: files,
+			Commits:      dedupeSubjects(shipped),
+			CI:           job.CI,
+		}
+	default:
+		level := SummaryLevel{
+			Header:       header,
+			WhatWorkedOn: append(plainBullets(groups), prBullets...),
+			Impact: fmt.Sprintf("%d change(s) by %d contributor(s) touching %d file(s) (+%d/-%d lines).",
+				len(job.Commits), len(out.Contributors), files.Files, files.Additions, files.Deletions),
+			Focus: heuristicFocus(groups),
+		}
+		if format == FormatLayman {
+			out.Layman = level
+		} else {
+			out.MildlyTechnical = level
+		}
+	}
+
+	pruneOutput(&out, format)
+	if job.Mode == ModePerContributor {
+		out.Sections = heuristicSections(job)
+	}
+	// Templates are English-only regardless of the requested language.
+	out.Language = DefaultLanguage
+
+	return SummarizeResult{
+		Payload: out,
+		Details: UsageDetails{Model: HeuristicModel, SchemaVersion: SchemaVersion},
+	}
+}
+
+func parseSubject(message string) (typ, scope, subject string) {
+	subject = strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	subject = prRefRe.ReplaceAllString(subject, "")
+	m := conventionalRe.FindStringSubmatch(subject)
+	if m == nil {
+		return "other", "", subject
+	}
+	typ = strings.ToLower(m[1])
+	if _, ok := commitTypeLabels[typ]; !ok {
+		return "other", "", subject
+	}
+	return typ, strings.TrimSpace(m[2]), strings.TrimSpace(m[4])
+}
+
+func normalizeSubject(s string) string {
+	return strings.Trim(nonWordRe.ReplaceAllString(strings.ToLower(s), " "), " ")
+}
+
+func groupCommits(commits []Commit) []commitGroup {
+	index := map[string]int{}
+	seen := map[string]bool{}
+	var groups []commitGroup
+
+	for _, c := range commits {
+		typ, scope, subject := parseSubject(c.Message)
+		if subject == "" {
+			continue
+		}
+		norm := normalizeSubject(subject)
+		if seen[typ+"|"+scope+"|"+norm] {
+			continue
+		}
+		seen[typ+"|"+scope+"|"+norm] = true
+
+		key := typ + "|" + scope
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, commitGroup{Type: typ, Scope: scope})
+		}
+		groups[i].Subjects = append(groups[i].Subjects, subject)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Subjects) > len(groups[j].Subjects)
+	})
+	return groups
+}
+
+func technicalBullets(groups []commitGroup) []string {
+	out := make([]string, 0, len(groups))
+	for _, g := range groups {
+		label := commitTypeLabels[g.Type]
+		if g.Scope != "" {
+			label += " (" + g.Scope + ")"
+		}
+		out = append(out, label+": "+strings.Join(g.Subjects, "; "))
+	}
+	return out
+}
+
+func plainBullets(groups []commitGroup) []string {
+	out := make([]string, 0, len(groups))
+	for _, g := range groups {
+		line := commitTypePlain[g.Type]
+		if g.Scope != "" {
+			line += " in " + g.Scope
+		}
+		out = append(out, fmt.Sprintf("%s (%d change(s))", line, len(g.Subjects)))
+	}
+	return out
+}
+
+// splitInProgress separates commits not yet on the default branch.
+func splitInProgress(commits []Commit) (shipped, wip []Commit) {
+	for _, c := range commits {
+		if c.InProgress {
+			wip = append(wip, c)
+		} else {
+			shipped = append(shipped, c)
+		}
+	}
+	return shipped, wip
+}
+
+func hasDiffs(commits []Commit) bool {
+	for _, c := range commits {
+		if len(c.Diff) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasInProgress(commits []Commit) bool {
+	for _, c := range commits {
+		if c.InProgress {
+			return true
+		}
+	}
+	return false
+}
+
+func inProgressBullets(commits []Commit) []string {
+	byBranch := map[string][]Commit{}
+	var order []string
+	for _, c := range commits {
+		b := strings.Join(c.Branches, ", ")
+		if _, ok := byBranch[b]; !ok {
+			order = append(order, b)
+		}
+		byBranch[b] = append(byBranch[b], c)
+	}
+
+	out := make([]string, 0, len(order))
+	for _, b := range order {
+		out = append(out, fmt.Sprintf("Work in progress on %s: %s", b, strings.Join(dedupeSubjects(byBranch[b]), "; ")))
+	}
+	return out
+}
+
+func releaseBullets(releases []Release) []string {
+	var out []string
+	for _, r := range releases {
+		line := "Released " + r.Tag
+		if r.Name != "" && r.Name != r.Tag {
+			line += " (" + r.Name + ")"
+		}
+		if r.Range != nil && r.Range.Commits > 0 {
+			line += fmt.Sprintf(", %d commit(s) since %s", r.Range.Commits, r.Range.From)
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func pullRequestBullets(prs []PullRequest) []string {
+	var merged, opened, closed, reviewed []string
+	for _, pr := range prs {
+		ref := fmt.Sprintf("#%d", pr.Number)
+		for _, e := range pr.Events {
+			switch e.Type {
+			case "merged":
+				merged = append(merged, ref)
+			case "opened":
+				opened = append(opened, ref)
+			case "closed":
+				closed = append(closed, ref)
+			}
+		}
+		if len(pr.Reviews) > 0 {
+			reviewed = append(reviewed, ref)
+		}
+	}
+
+	var out []string
+	for _, g := range []struct {
+		verb string
+		refs []string
+	}{{"Merged", merged}, {"Opened", opened}, {"Closed", closed}, {"Reviewed", reviewed}} {
+		if len(g.refs) > 0 {
+			out = append(out, fmt.Sprintf("%s pull request(s) %s", g.verb, strings.Join(g.refs, ", ")))
+		}
+	}
+	return out
+}
+
+func issueBullets(issues []Issue) []string {
+	var out []string
+	for _, is := range issues {
+		verb := ""
+		for _, e := range is.Events {
+			if e.Type == "closed" {
+				verb = "Closed"
+			} else if verb == "" {
+				verb = "Opened"
+			}
+		}
+		if verb == "" && is.Closing {
+			verb = "Worked towards closing"
+		}
+		if verb == "" {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s issue #%d: %s", verb, is.Number, is.Title))
+	}
+	return out
+}
+
+func ciBullets(ci *CIStatus) []string {
+	if ci == nil || ci.State != "failure" {
+		return nil
+	}
+	names := make([]string, 0, len(ci.Failing))
+	for _, f := range ci.Failing {
+		names = append(names, f.Name)
+	}
+	ref := ci.Ref
+	if ref == "" {
+		ref = "The branch head"
+	}
+	line := ref + " is red"
+	if ci.RedSince != nil {
+		line += " since " + ci.RedSince.UTC().Format("Jan 2 15:04 UTC")
+	}
+	return []string{fmt.Sprintf("%s (%s failing)", line, strings.Join(names, ", "))}
+}
+
+func heuristicFocus(groups []commitGroup) string {
+	if len(groups) == 0 {
+		return "No notable activity."
+	}
+	g := groups[0]
+	focus := commitTypePlain[g.Type]
+	if g.Scope != "" {
+		focus += " in " + g.Scope
+	}
+	return focus + "."
+}
+
+func dedupeSubjects(commits []Commit) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, c := range commits {
+		_, _, subject := parseSubject(c.Message)
+		norm := normalizeSubject(subject)
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		out = append(out, strings.TrimSpace(strings.SplitN(c.Message, "\n", 2)[0]))
+	}
+	return out
+}
+
+func heuristicSections(job SummarizeJob) []ContributorSection {
+	byHandle := map[string][]Commit{}
+	for _, c := range job.Commits {
+		for _, p := range c.credited() {
+			byHandle[p.Handle()] = append(byHandle[p.Handle()], c)
+		}
+	}
+
+	sections := make([]ContributorSection, 0, len(job.Contributors))
+	for _, a := range job.Contributors {
+		commits := byHandle[a.Handle]
+		sections = append(sections, ContributorSection{
+			Handle:       a.Handle,
+			Name:         a.Name,
+			Email:        a.Email,
+			CommitCount:  a.Commits,
+			CoAuthored:   a.CoAuthored,
+			FilesChanged: a.FilesChanged,
+			WhatWorkedOn: technicalBullets(groupCommits(commits)),
+			Commits:      dedupeSubjects(commits),
+		})
+	}
+	return sections
+}
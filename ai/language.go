@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+const DefaultLanguage = "en"
+
+var supportedLanguages = []language.Tag{
+	language.English,
+	language.BritishEnglish,
+	language.BrazilianPortuguese,
+	language.EuropeanPortuguese,
+	language.German,
+	language.Spanish,
+	language.LatinAmericanSpanish,
+	language.French,
+	language.Italian,
+	language.Dutch,
+	language.Polish,
+	language.Swedish,
+	language.Japanese,
+	language.Korean,
+	language.SimplifiedChinese,
+}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+// ResolveLanguage validates a BCP-47 tag and maps it to the closest supported
+// language. An empty tag selects English.
+func ResolveLanguage(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return DefaultLanguage, nil
+	}
+
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return "", fmt.Errorf("invalid language %q: %w", tag, err)
+	}
+
+	_, i, confidence := languageMatcher.Match(parsed)
+	if confidence < language.High {
+		return "", fmt.Errorf("unsupported language %q", tag)
+	}
+	return supportedLanguages[i].String(), nil
+}
+
+func languageInstruction(tag string) string {
+	if tag == "" || tag == DefaultLanguage {
+		return ""
+	}
+	name := tag
+	if parsed, err := language.Parse(tag); err == nil {
+		name = display.English.Tags().Name(parsed)
+	}
+	return fmt.Sprintf("\nWrite every human-readable string (title, headers, bullets, impact, focus) in %s (%s). Keep all JSON keys and commit SHAs exactly as given; do not translate them.", name, tag)
+}
@@ -9,9 +9,17 @@ import (
 
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/option"
+	"github.com/openai/openai-go/v2/shared"
+	"github.com/urizennnn/autostandup-reposcanner/config"
 )
 
-func Summarize(ctx context.Context, apiKey string, job SummarizeJob, format FormatType) (SummarizeResult, error) {
+// PromptVersion must be bumped whenever getSystemPrompt changes so cached
+// summaries produced by older prompts are not reused.
+const PromptVersion = "13"
+
+const ProviderOpenAI = "openai"
+
+func Summarize(ctx context.Context, apiKey, model string, pricing config.PricingTable, job SummarizeJob, format FormatType) (SummarizeResult, error) {
 	client := openai.NewClient(option.WithAPIKey(apiKey))
 
 	jobJSON, err := json.Marshal(job)
@@ -19,20 +27,23 @@ func Summarize(ctx context.Context, apiKey string, job SummarizeJob, format Form
 		return SummarizeResult{}, fmt.Errorf("marshal job: %w", err)
 	}
 
-	tool := openai.ChatCompletionFunctionTool(openai.FunctionDefinitionParam{
-		Name:        "emit_structured_standup",
-		Description: openai.String("Return the final standup payload in the exact structure the app expects."),
-		Parameters:  buildSchema(format),
-	})
-
 	params := openai.ChatCompletionNewParams{
-		Model: openai.ChatModelGPT4o,
+		Model: model,
 		Seed:  openai.Int(0),
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage(getSystemPrompt(format)),
+			openai.SystemMessage(getSystemPrompt(job, format)),
 			openai.UserMessage(fmt.Sprintf(`{"instruction":"Summarize commits into the exact structure","payload":%s}`, string(jobJSON))),
 		},
-		Tools: []openai.ChatCompletionToolUnionParam{tool},
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:        schemaName(format),
+					Description: openai.String("The final standup payload in the exact structure the app expects."),
+					Strict:      openai.Bool(true),
+					Schema:      buildSchema(format, job.Mode),
+				},
+			},
+		},
 	}
 
 	chatCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
@@ -43,192 +54,156 @@ func Summarize(ctx context.Context, apiKey string, job SummarizeJob, format Form
 		log.Printf("[ERROR] chat completion error: %v", err)
 		return SummarizeResult{}, err
 	}
-	if len(resp.Choices) == 0 || len(resp.Choices[0].Message.ToolCalls) == 0 {
-		return SummarizeResult{}, fmt.Errorf("model did not return tool call")
+	if len(resp.Choices) == 0 {
+		return SummarizeResult{}, fmt.Errorf("model returned no choices")
+	}
+	msg := resp.Choices[0].Message
+	if msg.Refusal != "" {
+		return SummarizeResult{}, fmt.Errorf("model refused: %s", msg.Refusal)
 	}
 	var out StandupPayload
-	for _, tc := range resp.Choices[0].Message.ToolCalls {
-		if tc.Function.Name == "emit_structured_standup" {
-			if err := json.Unmarshal([]byte(tc.Function.Arguments), &out); err != nil {
-				return SummarizeResult{}, fmt.Errorf("bad tool args: %w", err)
-			}
-			break
-		}
+	if err := json.Unmarshal([]byte(msg.Content), &out); err != nil {
+		return SummarizeResult{}, fmt.Errorf("bad structured output: %w", err)
 	}
 	if out.Repo == "" {
 		return SummarizeResult{}, fmt.Errorf("empty payload")
 	}
 
-	log.Printf("[INFO] summary generated repo=%s since=%s until=%s contributors=%d format=%s",
-		out.Repo, out.Window.Since, out.Window.Until, len(out.Contributors), format)
+	log.Printf("[INFO] summary generated repo=%s since=%s until=%s commits=%d format=%s",
+		out.Repo, out.Window.Since, out.Window.Until, len(job.Commits), format)
 
 	pruneOutput(&out, format)
+	if format == FormatTechnical {
+		out.Technical.CI = job.CI
+		if job.FilesChanged != nil {
+			out.Technical.FilesChanged = *job.FilesChanged
+		}
+	}
+	out.Language = job.Language
+	out.Releases = job.Releases
+	out.Components = job.Components
+	out.Contributors = BuildContributors(job.Commits)
+	if job.Mode == ModePerContributor {
+		fillSections(&out, job)
+	}
+
+	cachedTokens := resp.Usage.PromptTokensDetails.CachedTokens
+	if _, ok := pricing.Price(ProviderOpenAI, model); !ok {
+		log.Printf("[WARN] no pricing for %s/%s, reporting zero cost", ProviderOpenAI, model)
+	}
 
 	details := UsageDetails{
-		Model:            string(resp.Model),
-		PromptTokens:     resp.Usage.PromptTokens,
-		CompletionTokens: resp.Usage.CompletionTokens,
-		TotalTokens:      resp.Usage.TotalTokens,
-		EstimatedCost:    calculateCost(resp.Usage.PromptTokens, resp.Usage.CompletionTokens),
+		Provider:           ProviderOpenAI,
+		Model:              string(resp.Model),
+		PromptTokens:       resp.Usage.PromptTokens,
+		CachedPromptTokens: cachedTokens,
+		CompletionTokens:   resp.Usage.CompletionTokens,
+		TotalTokens:        resp.Usage.TotalTokens,
+		EstimatedCost:      pricing.Cost(ProviderOpenAI, model, resp.Usage.PromptTokens, cachedTokens, resp.Usage.CompletionTokens),
+		SchemaVersion:      SchemaVersion,
 	}
 
 	return SummarizeResult{Payload: out, Details: details}, nil
 }
 
-func calculateCost(promptTokens, completionTokens int64) float64 {
-	// GPT-4o pricing: $2.50/1M input, $10/1M output
-	return (float64(promptTokens) * 2.5 / 1_000_000) + (float64(completionTokens) * 10.0 / 1_000_000)
+func getSystemPrompt(job SummarizeJob, format FormatType) string {
+	prompt := formatPrompt(format)
+	if prompt == "" {
+		return ""
+	}
+	prompt += identityInstruction
+	if len(job.PullRequests) > 0 {
+		prompt += pullRequestInstruction
+	}
+	if len(job.Issues) > 0 {
+		prompt += issueInstruction
+	}
+	if job.CI != nil {
+		prompt += ciInstruction
+	}
+	if len(job.Releases) > 0 {
+		prompt += releaseInstruction
+	}
+	if hasInProgress(job.Commits) {
+		prompt += branchInstruction
+	}
+	if len(job.Components) > 1 {
+		prompt += componentInstruction
+	}
+	if hasDiffs(job.Commits) {
+		prompt += diffInstruction
+	}
+	if job.FilesChanged != nil && len(job.FilesChanged.Languages) > 0 {
+		prompt += fileBreakdownInstruction
+	}
+	if job.Mode == ModePerContributor {
+		prompt += perContributorInstruction
+	}
+	return prompt + languageInstruction(job.Language)
 }
 
-func getSystemPrompt(format FormatType) string {
+const identityInstruction = `
+Commits that share an authorLogin belong to the same contributor, even if their names or emails differ. Co-authors listed on a commit worked on it together with the author; credit them too.`
+
+const pullRequestInstruction = `
+payload.pullRequests lists pull request activity in the window: state transitions (opened, merged, closed) and reviews submitted. Mention it alongside the commits by number, e.g. "merged #123, reviewed #130", without double-counting work already described by the commits.`
+
+const issueInstruction = `
+payload.issues describes issues referenced by commits or pull requests (referencedBy, closing when a keyword like "fixes" was used) and issues opened or closed in the window. Describe outcomes in terms of the problem solved, using the issue titles and labels, e.g. "fixed #45 (checkout times out on large carts)".`
+
+const ciInstruction = `
+payload.ci is the CI state of the branch head (ref, sha) and, under merges, of merge commits in the window. When it is failing, call it out as a blocker in whatWorkedOn with the failing check names and, if redSince is set, since when, e.g. "main is red since 14:00 (lint, unit-tests failing)". Do not mention CI when it is green.`
+
+const releaseInstruction = `
+payload.releases lists releases and tags cut in the window, with their notes and the commit range each one covers. Lead with them, e.g. "released v1.4.0 (23 commits since v1.3.2)", and use the notes to describe what shipped rather than repeating every commit.`
+
+const branchInstruction = `
+Commits carry the branches they appear on. Those marked inProgress are not on the default branch yet: summarize them separately as work in progress, naming the branch, e.g. "WIP on feature/refunds: webhook retries", and keep them out of what was shipped.`
+
+const componentInstruction = `
+payload.components breaks the changes down by CODEOWNERS team (kind "team") or top-level directory (kind "directory"), largest first. Use it to say where the work landed, e.g. "mostly @acme/payments, with a small change in web".`
+
+const diffInstruction = `
+Some commits carry a diff: excerpts of their patches (truncated when marked). Use them to describe what actually changed, especially when the message is vague like "wip" or "fix". Describe behaviour, not line-by-line edits, and never quote secrets or credentials that appear in a patch.`
+
+const fileBreakdownInstruction = `
+payload.filesChanged breaks the changes down by language and by category (source, test, docs, config), largest first. Use it to characterise the work, e.g. "mostly Go backend, some TypeScript UI, plus test coverage".`
+
+const perContributorInstruction = `
+Per-contributor mode: the summary level above is the team-wide overview. Also emit one entry in "sections" for every entry in payload.contributors, copying its handle exactly. Each section covers only that contributor's commits (listed in its shas): whatWorkedOn bullets and commits[] for their own work.`
+
+func formatPrompt(format FormatType) string {
 	switch format {
 	case FormatTechnical:
-		return `You are AutoStandup's summarizer. Output ONE function call "emit_structured_standup" with JSON that matches the provided schema.
+		return `You are AutoStandup's summarizer. Respond only with JSON that matches the provided schema.
 Shape content technical level:
 - technical: header, whatWorkedOn bullets, filesChanged {files, additions, deletions}, commits[] (short, conventional commit style).
 	technical should be on the same understanding level as a software engineer it should contain the changes made and how it affected the codebase in regards to improvement and efficieny.
 Convert time stamps into human readable dates.
 Keep it concise, truthful, de-duplicate similar commits, and aggregate. Use the provided handle and projectName in headers like: "📊 **Daily Standup for @handle** – ProjectName and separate the commits summary for the different contributors". Include in the result a title for the standup.`
 	case FormatMildlyTechnical:
-		return `You are AutoStandup's summarizer. Output ONE function call "emit_structured_standup" with JSON that matches the provided schema.
+		return `You are AutoStandup's summarizer. Respond only with JSON that matches the provided schema.
 Shape content mildly-technical level only:
 	Convert time stamps into human readable dates.
 - mildlyTechnical: header, whatWorkedOn bullets, impact, focus.
 Keep it concise, truthful, de-duplicate similar commits, and aggregate. Use the provided handle and projectName in headers like: "📊 **Daily Standup for @handle** – ProjectName". Include in the result a title for the standup.`
 	case FormatLayman:
-		return `You are AutoStandup's summarizer. Output ONE function call "emit_structured_standup" with JSON that matches the provided schema.
+		return `You are AutoStandup's summarizer. Respond only with JSON that matches the provided schema.
 Shape content layman level only:
 	Convert time stamps into human readable dates.
 
 - layman: header, whatWorkedOn bullets (plain language), impact, focus.
 Keep it concise, truthful, de-duplicate similar commits, and aggregate. Use the provided handle and projectName in headers like: "📊 **Daily Standup for @handle** – ProjectName". Include in the result a title for the standup.`
 	default:
-		return ""
-	}
-}
-
-func buildSchema(format FormatType) openai.FunctionParameters {
-	baseProps := map[string]any{
-		"repo": map[string]any{"type": "string"},
-		"window": map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"since": map[string]any{"type": "string"},
-				"until": map[string]any{"type": "string"},
-			},
-			"required": []string{"since", "until"},
-		},
-		"contributors": map[string]any{
-			"type": "array",
-			"items": map[string]any{
-				"type": "object",
-				"properties": map[string]any{
-					"name":    map[string]any{"type": "string"},
-					"email":   map[string]any{"type": "string"},
-					"commits": map[string]any{"type": "integer"},
-				},
-				"required": []string{"name", "commits"},
-			},
-		},
-		"title": map[string]any{"type": "string"},
-	}
-
-	var required []string
-	switch format {
-	case FormatTechnical:
-		baseProps["technical"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"title":  map[string]any{"type": "string"},
-				"header": map[string]any{"type": "string"},
-				"whatWorkedOn": map[string]any{
-					"type":  "array",
-					"items": map[string]any{"type": "string"},
-				},
-				"filesChanged": map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"files":     map[string]any{"type": "integer"},
-						"additions": map[string]any{"type": "integer"},
-						"deletions": map[string]any{"type": "integer"},
-					},
-					"required": []string{"files", "additions", "deletions"},
-				},
-				"commits": map[string]any{
-					"type":  "array",
-					"items": map[string]any{"type": "string"},
-				},
-			},
-			"changes": map[string]any{
-				"type": "object",
-				"items": map[string]any{
-					"Code lines Added":        map[string]any{"type": "integer"},
-					"Code lines Deleted":      map[string]any{"type": "integer"},
-					"Code characters added":   map[string]any{"type": "integer"},
-					"Code characters deleted": map[string]any{"type": "integer"},
-				},
-			},
-			"required": []string{"header", "filesChanged", "title"},
+		spec, ok := lookupCustomFormat(format)
+		if !ok {
+			return ""
 		}
-		required = []string{"repo", "window", "technical", "title"}
-
-	case FormatMildlyTechnical:
-		baseProps["mildlyTechnical"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"title":  map[string]any{"type": "string"},
-				"header": map[string]any{"type": "string"},
-				"whatWorkedOn": map[string]any{
-					"type":  "array",
-					"items": map[string]any{"type": "string"},
-				},
-				"impact": map[string]any{"type": "string"},
-				"focus":  map[string]any{"type": "string"},
-			},
-			"required": []string{"header", "impact", "focus", "title"},
-		}
-		baseProps["changes"] = map[string]any{
-			"type": "object",
-			"items": map[string]any{
-				"Code lines Added":        map[string]any{"type": "integer"},
-				"Code lines Deleted":      map[string]any{"type": "integer"},
-				"Code characters added":   map[string]any{"type": "integer"},
-				"Code characters deleted": map[string]any{"type": "integer"},
-			},
-		}
-		required = []string{"repo", "window", "mildlyTechnical", "title"}
-
-	case FormatLayman:
-		baseProps["layman"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"title":  map[string]any{"type": "string"},
-				"header": map[string]any{"type": "string"},
-				"whatWorkedOn": map[string]any{
-					"type":  "array",
-					"items": map[string]any{"type": "string"},
-				},
-				"impact": map[string]any{"type": "string"},
-				"focus":  map[string]any{"type": "string"},
-			},
-			"required": []string{"header", "impact", "focus", "title"},
-		}
-		baseProps["changes"] = map[string]any{
-			"type": "object",
-			"items": map[string]any{
-				"Code lines Added":        map[string]any{"type": "integer"},
-				"Code lines Deleted":      map[string]any{"type": "integer"},
-				"Code characters added":   map[string]any{"type": "integer"},
-				"Code characters deleted": map[string]any{"type": "integer"},
-			},
-		}
-		required = []string{"repo", "window", "layman", "title"}
-	}
-
-	return openai.FunctionParameters{
-		"type":       "object",
-		"properties": baseProps,
-		"required":   required,
+		return `You are AutoStandup's summarizer. Respond only with JSON that matches the provided schema.
+Put the format-specific content in the "custom" object.
+Convert time stamps into human readable dates.
+Keep it concise, truthful, de-duplicate similar commits, and aggregate. Include in the result a title for the standup.
+` + spec.Prompt
 	}
 }
 
@@ -248,6 +223,13 @@ func pruneOutput(out *StandupPayload, format FormatType) {
 		out.Technical = TechnicalLevel{}
 		out.MildlyTechnical = SummaryLevel{}
 		out.Layman.WhatWorkedOn = pruneEmpty(out.Layman.WhatWorkedOn)
+	default:
+		out.Technical = TechnicalLevel{}
+		out.MildlyTechnical = SummaryLevel{}
+		out.Layman = SummaryLevel{}
+		if spec, ok := lookupCustomFormat(format); ok && out.Custom != nil {
+			out.Custom = pruneCustom(out.Custom, spec.Prune).(map[string]any)
+		}
 	}
 	out.Title = title // Restore the title after pruning
 }
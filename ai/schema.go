@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is bumped whenever the shape of StandupPayload changes in a way
+// consumers or cached responses need to know about.
+//
+// v2 added language, sections, releases, components, bots,
+// custom, technical.ci and the files changed breakdowns.
+const SchemaVersion = "v2"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func schemaName(format FormatType) string {
+	return fmt.Sprintf("standup_payload_%s_%s", SchemaVersion, format)
+}
+
+// buildSchema derives a strict JSON schema from StandupPayload, keeping only the
+// summary level that matches the requested format. Custom formats drop every
+// built-in level and contribute their own "custom" object instead. Sections
+// are only requested in per-contributor mode.
+func buildSchema(format FormatType, mode string) map[string]any {
+	spec, custom := lookupCustomFormat(format)
+
+	omit := map[string]bool{"sections": mode != ModePerContributor}
+	for _, level := range []string{"technical", "mildlyTechnical", "layman"} {
+		if custom || level != levelField(format) {
+			omit[level] = true
+		}
+	}
+	schema := objectSchema(reflect.TypeOf(StandupPayload{}), omit)
+
+	if custom {
+		schema["properties"].(map[string]any)["custom"] = spec.Schema
+		schema["required"] = append(schema["required"].([]string), "custom")
+	}
+	return schema
+}
+
+func levelField(format FormatType) string {
+	switch format {
+	case FormatMildlyTechnical:
+		return "mildlyTechnical"
+	case FormatLayman:
+		return "layman"
+	default:
+		return "technical"
+	}
+}
+
+// objectSchema follows the structured outputs subset: every property is listed
+// as required and additional properties are rejected.
+func objectSchema(t reflect.Type, omit map[string]bool) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("schema") == "-" {
+			continue
+		}
+		name := jsonName(f)
+		if name == "" || omit[name] {
+			continue
+		}
+		props[name] = typeSchema(f.Type)
+		required = append(required, name)
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t, nil)
+	default:
+		panic(fmt.Sprintf("ai: unsupported schema kind %s", t.Kind()))
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
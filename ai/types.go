@@ -6,10 +6,55 @@ type Commit struct {
 	SHA         string `json:"sha"`
 	AuthorName  string `json:"authorName"`
 	AuthorEmail string `json:"authorEmail"`
+	AuthorLogin string `json:"authorLogin,omitempty"`
 	Message     string `json:"message"`
 	Files       int    `json:"files"`
 	Additions   int    `json:"additions"`
 	Deletions   int    `json:"deletions"`
+
+	Date        time.Time  `json:"date"`
+	CoAuthors   []Person   `json:"coAuthors,omitempty"`
+	SignedOffBy []Person   `json:"signedOffBy,omitempty"`
+	ReviewedBy  []Person   `json:"reviewedBy,omitempty"`
+	IssueRefs   []IssueRef `json:"issueRefs,omitempty"`
+	Merge       bool       `json:"merge,omitempty"`
+
+	// Set only in multi-branch scans. InProgress commits are not on the
+	// default branch yet.
+	Branches   []string `json:"branches,omitempty"`
+	InProgress bool     `json:"inProgress,omitempty"`
+
+	// FileChanges backs the computed breakdowns and is not sent to the model.
+	// Diff is the budgeted subset with patches, set only when diffs are on.
+	FileChanges []FileChange `json:"-"`
+	Diff        []FileChange `json:"diff,omitempty"`
+}
+
+// FileChange is one file in a commit. Status is GitHub's: added, modified,
+// removed, renamed and so on.
+type FileChange struct {
+	Path         string `json:"path"`
+	PreviousPath string `json:"previousPath,omitempty"`
+	Status       string `json:"status,omitempty"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Patch        string `json:"patch,omitempty"`
+	Truncated    bool   `json:"truncated,omitempty"`
+	Language     string `json:"language,omitempty"`
+	Category     string `json:"category,omitempty"`
+}
+
+// IssueRef is an "#N" mention; Closing is set when a keyword such as
+// "fixes" precedes it.
+type IssueRef struct {
+	Number  int  `json:"number"`
+	Closing bool `json:"closing,omitempty"`
+}
+
+type Person struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Login string `json:"login,omitempty"`
 }
 
 type SummarizeJob struct {
@@ -18,19 +63,152 @@ type SummarizeJob struct {
 	Handle      string    `json:"handle"`
 	Since       time.Time `json:"since"`
 	Until       time.Time `json:"until"`
+	Language    string    `json:"language,omitempty"`
+	Mode        string    `json:"mode,omitempty"`
 	Commits     []Commit  `json:"commits"`
+
+	Contributors []ContributorActivity `json:"contributors,omitempty"`
+	PullRequests []PullRequest         `json:"pullRequests,omitempty"`
+	Issues       []Issue               `json:"issues,omitempty"`
+	CI           *CIStatus             `json:"ci,omitempty"`
+	Releases     []Release             `json:"releases,omitempty"`
+	Components   []Component           `json:"components,omitempty"`
+	FilesChanged *FilesChanged         `json:"filesChanged,omitempty"`
+}
+
+type PullRequest struct {
+	Number             int                `json:"number"`
+	Title              string             `json:"title"`
+	Author             string             `json:"author"`
+	State              string             `json:"state"`
+	Draft              bool               `json:"draft,omitempty"`
+	Base               string             `json:"base"`
+	Head               string             `json:"head"`
+	RequestedReviewers []string           `json:"requestedReviewers,omitempty"`
+	Events             []PullRequestEvent `json:"events,omitempty"`
+	Reviews            []Review           `json:"reviews,omitempty"`
+	IssueRefs          []IssueRef         `json:"issueRefs,omitempty"`
+}
+
+// PullRequestEvent is a state transition inside the scan window: opened,
+// merged or closed.
+type PullRequestEvent struct {
+	Type string    `json:"type"`
+	At   time.Time `json:"at"`
+	By   string    `json:"by,omitempty"`
+}
+
+type Issue struct {
+	Number        int          `json:"number"`
+	Title         string       `json:"title"`
+	State         string       `json:"state"`
+	Author        string       `json:"author,omitempty"`
+	Labels        []string     `json:"labels,omitempty"`
+	Events        []IssueEvent `json:"events,omitempty"`
+	ReferencedBy  []string     `json:"referencedBy,omitempty"`
+	Closing       bool         `json:"closing,omitempty"`
+	IsPullRequest bool         `json:"isPullRequest,omitempty"`
+}
+
+type IssueEvent struct {
+	Type string    `json:"type"`
+	At   time.Time `json:"at"`
+	By   string    `json:"by,omitempty"`
+}
+
+// CIStatus is the combined result of commit statuses and check runs on one
+// commit. State is success, failure or pending. RedSince is when the oldest
+// consecutive failing commit up to this one went red.
+type CIStatus struct {
+	SHA      string         `json:"sha"`
+	Ref      string         `json:"ref,omitempty"`
+	State    string         `json:"state"`
+	RedSince *time.Time     `json:"redSince,omitempty"`
+	Failing  []FailingCheck `json:"failing,omitempty"`
+	Merges   []CIStatus     `json:"merges,omitempty"`
+}
+
+type FailingCheck struct {
+	Name        string    `json:"name"`
+	Conclusion  string    `json:"conclusion"`
+	URL         string    `json:"url,omitempty"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// Release is a published release, or a bare tag when Name and Notes are
+// empty. Range is the commits since the release before it.
+type Release struct {
+	Tag        string        `json:"tag"`
+	Name       string        `json:"name,omitempty"`
+	Notes      string        `json:"notes,omitempty"`
+	Author     string        `json:"author,omitempty"`
+	Prerelease bool          `json:"prerelease,omitempty"`
+	At         time.Time     `json:"at"`
+	URL        string        `json:"url,omitempty"`
+	Range      *ReleaseRange `json:"range,omitempty"`
+}
+
+type ReleaseRange struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Commits int    `json:"commits,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+const (
+	ComponentTeam      = "team"
+	ComponentDirectory = "directory"
+)
+
+// Component is the share of the window's changes under one CODEOWNERS owner
+// set (Kind "team") or, for unowned files, one top-level directory.
+type Component struct {
+	Name         string       `json:"name"`
+	Kind         string       `json:"kind"`
+	Commits      int          `json:"commits"`
+	FilesChanged FilesChanged `json:"filesChanged"`
+}
+
+type Review struct {
+	Reviewer    string    `json:"reviewer"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+type ContributorActivity struct {
+	Handle       string       `json:"handle"`
+	Name         string       `json:"name"`
+	Email        string       `json:"email,omitempty"`
+	Login        string       `json:"login,omitempty"`
+	Commits      int          `json:"commits"`
+	CoAuthored   int          `json:"coAuthored,omitempty"`
+	FilesChanged FilesChanged `json:"filesChanged"`
+	SHAs         []string     `json:"shas"`
 }
 
 type Contributor struct {
-	Name    string `json:"name"`
-	Email   string `json:"email,omitempty"`
-	Commits int    `json:"commits"`
+	Name       string `json:"name"`
+	Email      string `json:"email,omitempty"`
+	Login      string `json:"login,omitempty"`
+	Commits    int    `json:"commits"`
+	CoAuthored int    `json:"coAuthored,omitempty"`
 }
 
+// FilesChanged totals a set of changes. Languages and Categories (source,
+// test, docs, config) are computed from file paths, never by the model.
 type FilesChanged struct {
-	Files     int `json:"files"`
-	Additions int `json:"additions"`
-	Deletions int `json:"deletions"`
+	Files      int           `json:"files"`
+	Additions  int           `json:"additions"`
+	Deletions  int           `json:"deletions"`
+	Languages  []ChangeShare `json:"languages,omitempty" schema:"-"`
+	Categories []ChangeShare `json:"categories,omitempty" schema:"-"`
+}
+
+type ChangeShare struct {
+	Name      string `json:"name"`
+	Files     int    `json:"files"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
 }
 
 type TechnicalLevel struct {
@@ -38,6 +216,7 @@ type TechnicalLevel struct {
 	WhatWorkedOn []string     `json:"whatWorkedOn,omitempty"`
 	FilesChanged FilesChanged `json:"filesChanged"`
 	Commits      []string     `json:"commits,omitempty"`
+	CI           *CIStatus    `json:"ci,omitempty" schema:"-"`
 }
 
 type SummaryLevel struct {
@@ -47,17 +226,42 @@ type SummaryLevel struct {
 	Focus        string   `json:"focus"`
 }
 
+// ContributorSection is one person's part of a per-contributor standup. The
+// model writes Handle, WhatWorkedOn and Commits; everything else is computed.
+type ContributorSection struct {
+	Handle       string       `json:"handle"`
+	Name         string       `json:"name" schema:"-"`
+	Email        string       `json:"email,omitempty" schema:"-"`
+	CommitCount  int          `json:"commitCount" schema:"-"`
+	CoAuthored   int          `json:"coAuthored,omitempty" schema:"-"`
+	FilesChanged FilesChanged `json:"filesChanged" schema:"-"`
+	WhatWorkedOn []string     `json:"whatWorkedOn,omitempty"`
+	Commits      []string     `json:"commits,omitempty"`
+}
+
+type BotActivity struct {
+	Name    string   `json:"name"`
+	Commits int      `json:"commits"`
+	Changes []string `json:"changes,omitempty"`
+}
+
 type StandupPayload struct {
-	Repo   string `json:"repo"`
-	Title  string `json:"title"`
-	Window struct {
+	Repo     string `json:"repo"`
+	Title    string `json:"title"`
+	Language string `json:"language,omitempty" schema:"-"`
+	Window   struct {
 		Since string `json:"since"`
 		Until string `json:"until"`
 	} `json:"window"`
-	Technical       TechnicalLevel `json:"technical"`
-	MildlyTechnical SummaryLevel   `json:"mildlyTechnical"`
-	Layman          SummaryLevel   `json:"layman"`
-	Contributors    []Contributor  `json:"contributors,omitempty"`
+	Technical       TechnicalLevel       `json:"technical"`
+	MildlyTechnical SummaryLevel         `json:"mildlyTechnical"`
+	Layman          SummaryLevel         `json:"layman"`
+	Contributors    []Contributor        `json:"contributors,omitempty" schema:"-"`
+	Sections        []ContributorSection `json:"sections,omitempty"`
+	Releases        []Release            `json:"releases,omitempty" schema:"-"`
+	Components      []Component          `json:"components,omitempty" schema:"-"`
+	Bots            []BotActivity        `json:"bots,omitempty" schema:"-"`
+	Custom          map[string]any       `json:"custom,omitempty" schema:"-"`
 }
 
 type FormatType string
@@ -69,11 +273,17 @@ const (
 )
 
 type UsageDetails struct {
-	Model            string  `json:"model"`
-	PromptTokens     int64   `json:"promptTokens"`
-	CompletionTokens int64   `json:"completionTokens"`
-	TotalTokens      int64   `json:"totalTokens"`
-	EstimatedCost    float64 `json:"estimatedCost"`
+	Provider           string  `json:"provider,omitempty"`
+	Model              string  `json:"model"`
+	PromptTokens       int64   `json:"promptTokens"`
+	CachedPromptTokens int64   `json:"cachedPromptTokens"`
+	CompletionTokens   int64   `json:"completionTokens"`
+	TotalTokens        int64   `json:"totalTokens"`
+	EstimatedCost      float64 `json:"estimatedCost"`
+	SchemaVersion      string  `json:"schemaVersion"`
+	CacheHit           bool    `json:"cacheHit"`
+	RequestedModel     string  `json:"requestedModel,omitempty"`
+	BudgetDecision     string  `json:"budgetDecision,omitempty"`
 }
 
 type SummarizeResult struct {
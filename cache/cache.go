@@ -1,39 +1,305 @@
 package cache
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
-type entry struct {
-	data      interface{}
+// EvictReason says why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry was the least recently used when the
+	// cache was full.
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the entry's TTL ran out.
+	EvictExpired
+)
+
+func (r EvictReason) String() string {
+	if r == EvictExpired {
+		return "expired"
+	}
+	return "capacity"
+}
+
+type Options[K comparable, V any] struct {
+	Size int
+	// JanitorInterval is how often expired entries are swept out. Zero
+	// disables the sweep; expired entries then stay until pushed out by
+	// newer ones, though Get never returns them.
+	JanitorInterval time.Duration
+	// OnEvict runs after an entry is dropped, outside the cache's locks.
+	OnEvict func(key K, val V, reason EvictReason)
+
+	// L2 is consulted by GetOrLoad and Lookup when the LRU misses, and is
+	// written whenever they load a value. Keys are formatted with fmt.Sprint
+	// and values encoded with Codec. Entries there live for L2TTL, usually
+	// much longer than in the LRU.
+	L2    Tier
+	Codec Codec[V]
+	L2TTL time.Duration
+}
+
+// Stats are counters since the cache was created.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Len         int
+
+	L2Hits   uint64
+	L2Misses uint64
+}
+
+type entry[V any] struct {
+	data      V
 	expiresAt time.Time
 }
 
-type Cache struct {
-	lru *lru.Cache[string, *entry]
+func (e entry[V]) expired(now time.Time) bool {
+	return now.After(e.expiresAt)
+}
+
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
 }
 
-func New(size int) (*Cache, error) {
-	l, err := lru.New[string, *entry](size)
+// Cache is a size-bounded LRU whose entries expire after a per-entry TTL.
+type Cache[K comparable, V any] struct {
+	lru     *lru.Cache[K, entry[V]]
+	onEvict func(K, V, EvictReason)
+	l2      Tier
+	codec   Codec[V]
+	l2TTL   time.Duration
+
+	mu       sync.Mutex
+	inflight map[K]*call[V]
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+	l2Hits      atomic.Uint64
+	l2Misses    atomic.Uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func New[K comparable, V any](opts Options[K, V]) (*Cache[K, V], error) {
+	if opts.L2 != nil && opts.Codec == nil {
+		return nil, errors.New("second tier needs a codec")
+	}
+	c := &Cache[K, V]{
+		onEvict:  opts.OnEvict,
+		l2:       opts.L2,
+		codec:    opts.Codec,
+		l2TTL:    opts.L2TTL,
+		inflight: map[K]*call[V]{},
+		stop:     make(chan struct{}),
+	}
+	l, err := lru.NewWithEvict(opts.Size, c.evicted)
 	if err != nil {
 		return nil, err
 	}
-	return &Cache{lru: l}, nil
+	c.lru = l
+	if opts.JanitorInterval > 0 {
+		go c.janitor(opts.JanitorInterval)
+	}
+	return c, nil
 }
 
-func (c *Cache) Get(key string) (interface{}, bool) {
+func (c *Cache[K, V]) Get(key K) (V, bool) {
 	e, ok := c.lru.Get(key)
-	if !ok || time.Now().After(e.expiresAt) {
-		return nil, false
+	if !ok || e.expired(time.Now()) {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
 	}
+	c.hits.Add(1)
 	return e.data, true
 }
 
-func (c *Cache) Set(key string, val interface{}, ttl time.Duration) {
-	c.lru.Add(key, &entry{
+func (c *Cache[K, V]) Set(key K, val V, ttl time.Duration) {
+	c.lru.Add(key, entry[V]{
 		data:      val,
 		expiresAt: time.Now().Add(ttl),
 	})
 }
+
+// Lookup is Get followed by the second tier. A value found there is kept in
+// the LRU for ttl.
+func (c *Cache[K, V]) Lookup(ctx context.Context, key K, ttl time.Duration) (V, bool) {
+	if v, ok := c.Get(key); ok {
+		return v, true
+	}
+	v, ok := c.getL2(ctx, key)
+	if ok {
+		c.Set(key, v, ttl)
+	}
+	return v, ok
+}
+
+// GetOrLoad returns the cached value for key from either tier, or calls load
+// and caches its result, for ttl in the LRU. Concurrent callers missing the
+// same key share one lookup and load; the others wait for it or for ctx.
+// Errors are returned to every waiter and not cached, except that a load
+// which failed because its own caller's context ended is retried by a waiter
+// whose ctx is still live, so one cancelled job does not fail the others.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, ttl time.Duration, load func() (V, error)) (V, error) {
+	for {
+		if v, ok := c.Get(key); ok {
+			return v, nil
+		}
+
+		c.mu.Lock()
+		cl, waiting := c.inflight[key]
+		if !waiting {
+			cl = &call[V]{done: make(chan struct{})}
+			c.inflight[key] = cl
+		}
+		c.mu.Unlock()
+
+		if !waiting {
+			return c.load(ctx, key, ttl, cl, load)
+		}
+		select {
+		case <-cl.done:
+			if isContextErr(cl.err) && ctx.Err() == nil {
+				continue
+			}
+			return cl.val, cl.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// load fills cl for the waiters on key, from the second tier or load.
+func (c *Cache[K, V]) load(ctx context.Context, key K, ttl time.Duration, cl *call[V], load func() (V, error)) (V, error) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	if v, ok := c.getL2(ctx, key); ok {
+		c.Set(key, v, ttl)
+		cl.val = v
+		return v, nil
+	}
+	cl.val, cl.err = load()
+	if cl.err == nil {
+		c.Set(key, cl.val, ttl)
+		c.setL2(ctx, key, cl.val)
+	}
+	return cl.val, cl.err
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// getL2 reads key from the second tier. Errors are logged and count as a
+// miss, so a broken tier only costs the requests it would have saved.
+func (c *Cache[K, V]) getL2(ctx context.Context, key K) (V, bool) {
+	var zero V
+	if c.l2 == nil {
+		return zero, false
+	}
+	b, ok, err := c.l2.Get(ctx, fmt.Sprint(key))
+	if err != nil {
+		log.Printf("[WARN] cache tier lookup %v: %v", key, err)
+	}
+	if !ok {
+		c.l2Misses.Add(1)
+		return zero, false
+	}
+	v, err := c.codec.Decode(b)
+	if err != nil {
+		log.Printf("[WARN] cache tier decode %v: %v", key, err)
+		c.l2Misses.Add(1)
+		return zero, false
+	}
+	c.l2Hits.Add(1)
+	return v, true
+}
+
+func (c *Cache[K, V]) setL2(ctx context.Context, key K, val V) {
+	if c.l2 == nil {
+		return
+	}
+	b, err := c.codec.Encode(val)
+	if err != nil {
+		log.Printf("[WARN] cache tier encode %v: %v", key, err)
+		return
+	}
+	if err := c.l2.Set(ctx, fmt.Sprint(key), b, c.l2TTL); err != nil {
+		log.Printf("[WARN] cache tier write %v: %v", key, err)
+	}
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+		Len:         c.lru.Len(),
+		L2Hits:      c.l2Hits.Load(),
+		L2Misses:    c.l2Misses.Load(),
+	}
+}
+
+// Close stops the janitor. The cache stays usable.
+func (c *Cache[K, V]) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+func (c *Cache[K, V]) evicted(key K, e entry[V]) {
+	reason := EvictCapacity
+	if e.expired(time.Now()) {
+		reason = EvictExpired
+		c.expirations.Add(1)
+	} else {
+		c.evictions.Add(1)
+	}
+	if c.onEvict != nil {
+		c.onEvict(key, e.data, reason)
+	}
+}
+
+func (c *Cache[K, V]) janitor(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+			c.sweep()
+		}
+	}
+}
+
+// sweep removes expired entries without touching their recency.
+func (c *Cache[K, V]) sweep() {
+	now := time.Now()
+	for _, key := range c.lru.Keys() {
+		if e, ok := c.lru.Peek(key); ok && e.expired(now) {
+			c.lru.Remove(key)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskHeaderBytes holds each file's expiry as Unix seconds; zero never
+// expires.
+const diskHeaderBytes = 8
+
+// diskEvictTarget is the share of maxBytes kept after an eviction pass, so
+// a full tier does not evict on every write.
+const diskEvictTarget = 0.9
+
+type diskFile struct {
+	size   int64
+	usedAt time.Time
+}
+
+// DiskTier keeps one file per entry under dir, named by the hash of its key.
+// It is meant for immutable data on a single node: entries survive restarts,
+// and once the files pass maxBytes the least recently read ones are deleted.
+type DiskTier struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	files map[string]diskFile
+	size  int64
+}
+
+// NewDiskTier creates dir if needed and indexes the entries already in it.
+// Only files in the tier's own layout, dir/xx/<sha256 hex>, are indexed or
+// ever deleted, so anything else sharing dir is left alone.
+func NewDiskTier(dir string, maxBytes int64) (*DiskTier, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir: %w", err)
+	}
+	t := &DiskTier{dir: dir, maxBytes: maxBytes, files: map[string]diskFile{}}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		if d.IsDir() {
+			if filepath.Dir(p) != dir || !isShardName(d.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Dir(p) == dir {
+			return nil
+		}
+		shard := filepath.Base(filepath.Dir(p))
+		name, tmp := d.Name(), false
+		if i := strings.IndexByte(name, '.'); i >= 0 && strings.HasSuffix(name, ".tmp") {
+			name, tmp = name[:i], true
+		}
+		if !isEntryName(name) || name[:2] != shard {
+			return nil
+		}
+		if tmp {
+			// Left behind by a write that never finished.
+			return os.Remove(p)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		t.files[p] = diskFile{size: info.Size(), usedAt: info.ModTime()}
+		t.size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("indexing cache dir: %w", err)
+	}
+	t.mu.Lock()
+	t.evict()
+	t.mu.Unlock()
+	log.Printf("[INFO] disk cache %s entries=%d bytes=%d", dir, len(t.files), t.size)
+	return t, nil
+}
+
+// isShardName matches the two hex digits of a subdirectory made by path.
+func isShardName(name string) bool {
+	return len(name) == 2 && isLowerHex(name)
+}
+
+// isEntryName matches the hex SHA-256 that path names entries with.
+func isEntryName(name string) bool {
+	return len(name) == sha256.Size*2 && isLowerHex(name)
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *DiskTier) Get(_ context.Context, key string) ([]byte, bool, error) {
+	p := t.path(key)
+	b, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading cache file: %w", err)
+	}
+	if len(b) < diskHeaderBytes {
+		t.remove(p)
+		return nil, false, nil
+	}
+	now := time.Now()
+	if exp := int64(binary.BigEndian.Uint64(b)); exp != 0 && now.Unix() > exp {
+		t.remove(p)
+		return nil, false, nil
+	}
+
+	// The modification time doubles as the last read, so recency survives
+	// restarts too.
+	_ = os.Chtimes(p, now, now)
+	t.mu.Lock()
+	if f, ok := t.files[p]; ok {
+		f.usedAt = now
+		t.files[p] = f
+	}
+	t.mu.Unlock()
+	return b[diskHeaderBytes:], true, nil
+}
+
+func (t *DiskTier) Set(_ context.Context, key string, val []byte, ttl time.Duration) error {
+	var exp int64
+	if ttl > 0 {
+		exp = time.Now().Add(ttl).Unix()
+	}
+	b := make([]byte, diskHeaderBytes+len(val))
+	binary.BigEndian.PutUint64(b, uint64(exp))
+	copy(b[diskHeaderBytes:], val)
+
+	p := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+	// Written aside and renamed, so readers never see half a file.
+	tmp, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.size += int64(len(b)) - t.files[p].size
+	t.files[p] = diskFile{size: int64(len(b)), usedAt: time.Now()}
+	t.evict()
+	return nil
+}
+
+// path spreads entries over 256 subdirectories by the first byte of the
+// key's hash.
+func (t *DiskTier) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(t.dir, name[:2], name)
+}
+
+func (t *DiskTier) remove(p string) {
+	os.Remove(p)
+	t.mu.Lock()
+	t.size -= t.files[p].size
+	delete(t.files, p)
+	t.mu.Unlock()
+}
+
+// evict deletes the least recently read files until the tier is back under
+// diskEvictTarget of maxBytes. t.mu must be held.
+func (t *DiskTier) evict() {
+	if t.maxBytes <= 0 || t.size <= t.maxBytes {
+		return
+	}
+	paths := make([]string, 0, len(t.files))
+	for p := range t.files {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return t.files[paths[i]].usedAt.Before(t.files[paths[j]].usedAt)
+	})
+
+	target := int64(float64(t.maxBytes) * diskEvictTarget)
+	removed := 0
+	for _, p := range paths {
+		if t.size <= target {
+			break
+		}
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[WARN] disk cache evict %s: %v", p, err)
+			continue
+		}
+		t.size -= t.files[p].size
+		delete(t.files, p)
+		removed++
+	}
+	log.Printf("[INFO] disk cache evicted=%d bytes=%d", removed, t.size)
+}
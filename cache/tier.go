@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Tier is a slower, shared store checked after the in-process LRU misses.
+// It holds encoded values; Cache turns them back into V with a Codec.
+type Tier interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+}
+
+type Codec[V any] interface {
+	Encode(V) ([]byte, error)
+	Decode([]byte) (V, error)
+}
+
+// JSONCodec encodes values as JSON, the format used for everything else
+// the service keeps in Redis.
+type JSONCodec[V any] struct{}
+
+func (JSONCodec[V]) Encode(v V) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec[V]) Decode(b []byte) (V, error) {
+	var v V
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// RedisTier keeps entries in Redis under prefix+key, so every replica and
+// every restart can read them.
+type RedisTier struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+func NewRedisTier(rdb *redis.Client, prefix string) *RedisTier {
+	return &RedisTier{rdb: rdb, prefix: prefix}
+}
+
+func (t *RedisTier) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b, err := t.rdb.Get(ctx, t.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading cache entry: %w", err)
+	}
+	return b, true, nil
+}
+
+func (t *RedisTier) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	return t.rdb.Set(ctx, t.prefix+key, val, ttl).Err()
+}
@@ -0,0 +1,82 @@
+// Package codec encodes published results as JSON, MessagePack or protobuf.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+	standupv1 "github.com/urizennnn/autostandup-reposcanner/proto/standup/v1"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type Encoding string
+
+const (
+	JSON        Encoding = "json"
+	MessagePack Encoding = "msgpack"
+	Protobuf    Encoding = "protobuf"
+)
+
+// Resolve maps a job's encoding to a known one. Empty selects JSON.
+func Resolve(name string) (Encoding, error) {
+	switch e := Encoding(strings.ToLower(strings.TrimSpace(name))); e {
+	case "":
+		return JSON, nil
+	case JSON, MessagePack, Protobuf:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", name)
+}
+
+// TestResult is what a test standup entry carries: the payload with the
+// usage details that produced it.
+type TestResult struct {
+	Payload       ai.StandupPayload `json:"payload"`
+	Details       ai.UsageDetails   `json:"details"`
+	IsTestStandup bool              `json:"isTestStandup"`
+}
+
+// EncodePayload encodes p as the standup.v1 StandupPayload message.
+func EncodePayload(enc Encoding, p ai.StandupPayload) ([]byte, error) {
+	return encode(enc, p, &standupv1.StandupPayload{})
+}
+
+// EncodeTestResult encodes r as the standup.v1 StandupResult message.
+func EncodeTestResult(enc Encoding, r TestResult) ([]byte, error) {
+	return encode(enc, r, &standupv1.StandupResult{})
+}
+
+// encode writes v in enc. MessagePack uses the JSON field names; protobuf
+// goes through the JSON form, whose keys are the proto fields' JSON names,
+// so the ai types need no hand-written mapping. Keys the schema does not
+// know yet are dropped rather than failing the entry.
+func encode(enc Encoding, v any, msg proto.Message) ([]byte, error) {
+	switch enc {
+	case JSON:
+		return json.Marshal(v)
+	case MessagePack:
+		var buf bytes.Buffer
+		e := msgpack.NewEncoder(&buf)
+		e.SetCustomStructTag("json")
+		e.UseCompactInts(true)
+		if err := e.Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Protobuf:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, msg); err != nil {
+			return nil, fmt.Errorf("mapping to %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+		}
+		return proto.Marshal(msg)
+	}
+	return nil, fmt.Errorf("unknown encoding %q", enc)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ModelPrice is expressed in USD per million tokens.
+type ModelPrice struct {
+	Input       float64 `json:"input"`
+	CachedInput float64 `json:"cachedInput"`
+	Output      float64 `json:"output"`
+}
+
+// PricingTable is keyed by "provider/model".
+type PricingTable map[string]ModelPrice
+
+var DefaultPricing = PricingTable{
+	"openai/gpt-4o":       {Input: 2.50, CachedInput: 1.25, Output: 10.00},
+	"openai/gpt-4o-mini":  {Input: 0.15, CachedInput: 0.075, Output: 0.60},
+	"openai/gpt-4.1":      {Input: 2.00, CachedInput: 0.50, Output: 8.00},
+	"openai/gpt-4.1-mini": {Input: 0.40, CachedInput: 0.10, Output: 1.60},
+	"openai/gpt-4.1-nano": {Input: 0.10, CachedInput: 0.025, Output: 0.40},
+}
+
+// Decode lets envconfig read the table from a JSON object. Entries are merged
+// over DefaultPricing so overriding one model does not drop the others.
+func (t *PricingTable) Decode(value string) error {
+	table := PricingTable{}
+	for k, v := range DefaultPricing {
+		table[k] = v
+	}
+
+	var overrides PricingTable
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return fmt.Errorf("pricing table: %w", err)
+	}
+	for k, v := range overrides {
+		if !strings.Contains(k, "/") {
+			return fmt.Errorf("pricing table: key %q must be provider/model", k)
+		}
+		table[k] = v
+	}
+
+	*t = table
+	return nil
+}
+
+func (t PricingTable) Price(provider, model string) (ModelPrice, bool) {
+	p, ok := t[provider+"/"+model]
+	return p, ok
+}
+
+// Cost prices a completion. cachedTokens is the part of promptTokens that was
+// served from the provider's prompt cache.
+func (t PricingTable) Cost(provider, model string, promptTokens, cachedTokens, completionTokens int64) float64 {
+	p, ok := t.Price(provider, model)
+	if !ok {
+		return 0
+	}
+	uncached := promptTokens - cachedTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+	return (float64(uncached)*p.Input + float64(cachedTokens)*p.CachedInput + float64(completionTokens)*p.Output) / 1_000_000
+}
@@ -27,16 +27,62 @@ type Config struct {
 	GithubPrivateKey string `envconfig:"APP_GITHUB_PRIVATE_KEY" validate:"required"`
 	GithubClientID   string `split_words:"true" validate:"required"`
 
+	// Author identity
+	AuthorAliases map[string]string `split_words:"true"`
+	BotEmails     []string          `split_words:"true"`
+	BotCommits    string            `split_words:"true" default:"separate" validate:"oneof=separate exclude"`
+
 	// OPENAI
-	OpenaiApiKey string `split_words:"true" validate:"required"`
+	OpenaiApiKey        string       `split_words:"true" validate:"required"`
+	OpenaiModel         string       `split_words:"true" default:"gpt-4o" validate:"required"`
+	OpenaiFallbackModel string       `split_words:"true" default:"gpt-4o-mini"`
+	PricingTable        PricingTable `split_words:"true" default:"{}"`
+	FormatsDir          string       `split_words:"true"`
+
+	// Budgets in USD per UTC day; 0 disables the check
+	InstallationDailyBudget float64 `split_words:"true" default:"0" validate:"gte=0"`
+	GlobalDailyBudget       float64 `split_words:"true" default:"0" validate:"gte=0"`
+
+	// Diff excerpts for jobs with includeDiffs
+	DiffBudgetBytes int      `split_words:"true" default:"60000" validate:"gt=0"`
+	DiffFileBytes   int      `split_words:"true" default:"4000" validate:"gt=0"`
+	DiffSkipPaths   []string `split_words:"true" default:"**/package-lock.json,**/yarn.lock,**/pnpm-lock.yaml,**/go.sum,**/Cargo.lock,**/poetry.lock,**/Gemfile.lock,**/composer.lock,**/*.min.js,**/*.min.css,**/*.map,**/*.pb.go,**/*_generated.go,**/*.gen.go,**/vendor/**,**/node_modules/**,**/dist/**"`
+
+	// Conditional GitHub requests
+	EtagStore         string        `split_words:"true" default:"memory" validate:"oneof=memory redis off"`
+	EtagCacheSize     int           `split_words:"true" default:"5000" validate:"gt=0"`
+	EtagCacheMaxBytes int64         `split_words:"true" default:"67108864" validate:"gt=0"`
+	EtagCacheTTL      time.Duration `split_words:"true" default:"168h" validate:"gt=0"`
+
+	// Result delivery
+	DeliveryMaxAttempts int           `split_words:"true" default:"5" validate:"gt=0"`
+	DeliveryBackoffMin  time.Duration `split_words:"true" default:"2s" validate:"gt=0"`
+	DeliveryBackoffMax  time.Duration `split_words:"true" default:"1m" validate:"gt=0"`
+	DeliveryTimeout     time.Duration `split_words:"true" default:"15s" validate:"gt=0"`
+	DeliveryStatusTTL   time.Duration `split_words:"true" default:"168h" validate:"gt=0"`
+	SMTPAddr            string        `split_words:"true"`
+	SMTPUsername        string        `split_words:"true"`
+	SMTPFrom            string        `split_words:"true"`
 
 	// Performance tuning
-	WorkerCount       int           `split_words:"true" default:"5" validate:"gt=0"`
-	GithubConcurrency int           `split_words:"true" default:"10" validate:"gt=0"`
-	GithubRateLimit   int           `split_words:"true" default:"80" validate:"gt=0"`
-	OpenaiRateLimit   int           `split_words:"true" default:"50" validate:"gt=0"`
-	CacheSize         int           `split_words:"true" default:"1000" validate:"gt=0"`
-	MessageTimeout    time.Duration `split_words:"true" default:"5m" validate:"gt=0"`
+	WorkerCount         int           `split_words:"true" default:"5" validate:"gt=0"`
+	GithubConcurrency   int           `split_words:"true" default:"10" validate:"gt=0"`
+	GithubRateLimit     int           `split_words:"true" default:"80" validate:"gt=0"`
+	OpenaiRateLimit     int           `split_words:"true" default:"50" validate:"gt=0"`
+	MaxPullRequests     int           `split_words:"true" default:"50" validate:"gt=0"`
+	MaxIssues           int           `split_words:"true" default:"50" validate:"gt=0"`
+	CIMergeCommits      bool          `split_words:"true" default:"false"`
+	MaxBranches         int           `split_words:"true" default:"20" validate:"gt=0"`
+	CommitStatsSource   string        `split_words:"true" default:"rest" validate:"oneof=auto rest graphql"`
+	GraphqlMinCommits   int           `split_words:"true" default:"30" validate:"gt=0"`
+	CacheSize           int           `split_words:"true" default:"1000" validate:"gt=0"`
+	CacheSweepInterval  time.Duration `split_words:"true" default:"1m" validate:"gte=0"`
+	CommitCacheStore    string        `split_words:"true" default:"memory" validate:"oneof=memory redis disk"`
+	CommitCacheTTL      time.Duration `split_words:"true" default:"720h" validate:"gt=0"`
+	CommitCacheDir      string        `split_words:"true" default:"/var/cache/reposcanner/commits"`
+	CommitCacheMaxBytes int64         `split_words:"true" default:"1073741824" validate:"gt=0"`
+	SummaryCacheTTL     time.Duration `split_words:"true" default:"24h" validate:"gt=0"`
+	MessageTimeout      time.Duration `split_words:"true" default:"5m" validate:"gt=0"`
 
 	// Redis tuning
 	RedisStreamMaxLen int           `split_words:"true" default:"1000" validate:"gt=0"`
@@ -0,0 +1,321 @@
+// Package delivery sends published results straight to a job's webhook,
+// Slack channel or mailbox, retrying each one on its own and recording how
+// it went.
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/urizennnn/autostandup-reposcanner/config"
+)
+
+const (
+	SecretWebhookSecret config.SecretKey = "APP_WEBHOOK_SECRET"
+	SecretSMTPPassword  config.SecretKey = "APP_SMTP_PASSWORD"
+)
+
+// sink sends one message to one target. Errors wrapped with permanent are
+// not retried.
+type sink interface {
+	name() string
+	target() string
+	send(ctx context.Context, m Message) error
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// retryAfterError asks for the next attempt no sooner than after.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e retryAfterError) Error() string { return e.err.Error() }
+func (e retryAfterError) Unwrap() error { return e.err }
+
+// ValidateTargets checks what can be checked from the job alone: URLs are
+// absolute https to a public host and email addresses parse. Missing secrets or SMTP settings
+// surface as failed deliveries instead.
+func ValidateTargets(t *Targets) error {
+	if t == nil {
+		return nil
+	}
+	if t.Webhook != nil {
+		if err := validateURL(t.Webhook.URL); err != nil {
+			return fmt.Errorf("webhook delivery: %w", err)
+		}
+	}
+	if t.Slack != nil {
+		if err := validateURL(t.Slack.WebhookURL); err != nil {
+			return fmt.Errorf("slack delivery: %w", err)
+		}
+	}
+	if t.Email != nil {
+		if len(t.Email.To) == 0 {
+			return errors.New("email delivery: no recipients")
+		}
+		for _, to := range t.Email.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return fmt.Errorf("email delivery: bad recipient %q: %w", to, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("bad url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url must be absolute https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url host %s: %w", host, errPrivateAddress)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !publicIP(ip) {
+		return fmt.Errorf("url host %s: %w", host, errPrivateAddress)
+	}
+	return nil
+}
+
+// errPrivateAddress refuses webhook and Slack targets inside the network.
+// validateURL catches literal addresses; dialPublic catches names that
+// resolve to one.
+var errPrivateAddress = errors.New("address is not public")
+
+// reservedPrefixes are ranges netip does not count as private but which are
+// not reachable on the internet either: "this network" and carrier-grade NAT.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// publicIP rejects loopback, private, link-local, multicast, unspecified and
+// reserved addresses.
+func publicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range reservedPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublic is a net.Dialer Control func that refuses non-public addresses
+// once DNS has been resolved, so a name pointing inside cannot slip through.
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicIP(ap.Addr()) {
+		return fmt.Errorf("%s: %w", ap.Addr(), errPrivateAddress)
+	}
+	return nil
+}
+
+// Dispatcher runs deliveries in the background so a slow or failing target
+// never holds up a worker. Each delivery's status is kept in Redis under
+// delivery:<entry id>, one hash field per sink.
+type Dispatcher struct {
+	rdb *redis.Client
+	cfg *config.Config
+
+	// http and checkURL are fields so tests can point deliveries at a
+	// local server.
+	http          *http.Client
+	checkURL      func(string) error
+	webhookSecret string
+	smtpPassword  string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(cfg *config.Config, rdb *redis.Client) *Dispatcher {
+	// Both secrets are optional; targets that need a missing one fail.
+	webhookSecret, _ := config.FetchSecretByName(SecretWebhookSecret)
+	smtpPassword, _ := config.FetchSecretByName(SecretSMTPPassword)
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// The dial check has to see the target itself, not a proxy.
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialPublic,
+	}).DialContext
+	return &Dispatcher{
+		rdb: rdb,
+		cfg: cfg,
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.DeliveryTimeout,
+			// A redirect would turn the POST into a GET; report it instead.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		checkURL:      validateURL,
+		webhookSecret: webhookSecret,
+		smtpPassword:  smtpPassword,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Deliver starts one delivery per target and returns at once.
+func (d *Dispatcher) Deliver(t *Targets, m Message) {
+	for _, s := range d.sinks(t) {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.run(s, m)
+		}()
+	}
+}
+
+// Close waits up to grace for running deliveries, then cancels the rest,
+// which are recorded as failed.
+func (d *Dispatcher) Close(grace time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(grace):
+		log.Printf("[WARN] delivery shutdown grace elapsed, cancelling pending deliveries")
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) sinks(t *Targets) []sink {
+	if t == nil {
+		return nil
+	}
+	var out []sink
+	if t.Webhook != nil {
+		out = append(out, d.checked(&webhookSink{url: t.Webhook.URL, secret: d.webhookSecret, http: d.http}, t.Webhook.URL))
+	}
+	if t.Slack != nil {
+		out = append(out, d.checked(&slackSink{url: t.Slack.WebhookURL, http: d.http}, t.Slack.WebhookURL))
+	}
+	if t.Email != nil {
+		out = append(out, &emailSink{
+			addr:     d.cfg.SMTPAddr,
+			username: d.cfg.SMTPUsername,
+			password: d.smtpPassword,
+			from:     d.cfg.SMTPFrom,
+			to:       t.Email.To,
+			subject:  t.Email.Subject,
+		})
+	}
+	return out
+}
+
+// checked returns s, or a stand-in that fails at once if checkURL refuses
+// the URL it posts to.
+func (d *Dispatcher) checked(s sink, raw string) sink {
+	if err := d.checkURL(raw); err != nil {
+		return rejectedSink{sink: s, err: permanent(err)}
+	}
+	return s
+}
+
+type rejectedSink struct {
+	sink
+	err error
+}
+
+func (r rejectedSink) send(context.Context, Message) error { return r.err }
+
+// run sends m through s, retrying transient failures with exponential
+// backoff up to DeliveryMaxAttempts, and records the state after each
+// attempt.
+func (d *Dispatcher) run(s sink, m Message) {
+	st := Status{Sink: s.name(), Target: s.target()}
+	backoff := d.cfg.DeliveryBackoffMin
+	for {
+		st.Attempts++
+		err := s.send(d.ctx, m)
+		if err == nil {
+			st.State, st.Error = StateDelivered, ""
+			d.record(m.ID, st)
+			log.Printf("[INFO] delivered %s id=%s target=%s attempts=%d", st.Sink, m.ID, st.Target, st.Attempts)
+			return
+		}
+
+		st.Error = err.Error()
+		var perm permanentError
+		if errors.As(err, &perm) || st.Attempts >= d.cfg.DeliveryMaxAttempts || d.ctx.Err() != nil {
+			st.State = StateFailed
+			d.record(m.ID, st)
+			log.Printf("[ERROR] delivery %s id=%s target=%s failed after %d attempts: %v", st.Sink, m.ID, st.Target, st.Attempts, err)
+			return
+		}
+
+		st.State = StateRetrying
+		d.record(m.ID, st)
+		wait := backoff
+		var ra retryAfterError
+		if errors.As(err, &ra) && ra.after > wait {
+			wait = min(ra.after, d.cfg.DeliveryBackoffMax)
+		}
+		log.Printf("[WARN] delivery %s id=%s attempt %d/%d failed, retrying in %v: %v",
+			st.Sink, m.ID, st.Attempts, d.cfg.DeliveryMaxAttempts, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-d.ctx.Done():
+		}
+		backoff = min(backoff*2, d.cfg.DeliveryBackoffMax)
+	}
+}
+
+func (d *Dispatcher) record(id string, st Status) {
+	st.UpdatedAt = time.Now().UTC()
+	b, err := json.Marshal(st)
+	if err != nil {
+		log.Printf("[WARN] delivery status encode: %v", err)
+		return
+	}
+	// Recorded even while shutting down, so use a context of its own.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	key := "delivery:" + id
+	pipe := d.rdb.TxPipeline()
+	pipe.HSet(ctx, key, st.Sink, b)
+	pipe.Expire(ctx, key, d.cfg.DeliveryStatusTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[WARN] delivery status write %s: %v", key, err)
+	}
+}
@@ -0,0 +1,402 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/urizennnn/autostandup-reposcanner/config"
+)
+
+const testSecret = "s3cret"
+
+// newTestDispatcher returns a dispatcher that trusts srv's certificate,
+// accepts any URL and records status in a throwaway Redis. Backoff is short
+// so retries do not slow the tests down.
+func newTestDispatcher(t *testing.T, srv *httptest.Server) (*Dispatcher, *redis.Client) {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	t.Setenv(string(SecretWebhookSecret), testSecret)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	cfg := &config.Config{
+		DeliveryMaxAttempts: 3,
+		DeliveryBackoffMin:  time.Millisecond,
+		DeliveryBackoffMax:  10 * time.Millisecond,
+		DeliveryTimeout:     5 * time.Second,
+		DeliveryStatusTTL:   time.Hour,
+	}
+	d := NewDispatcher(cfg, rdb)
+	if srv != nil {
+		// Keep the dispatcher's timeout and redirect policy.
+		d.http.Transport = srv.Client().Transport
+	}
+	d.checkURL = func(string) error { return nil }
+	return d, rdb
+}
+
+func testMessage() Message {
+	m := Message{ID: "1700000000000-0", Repo: "o/r"}
+	m.Payload.Repo = "o/r"
+	m.Payload.Title = "Standup for o/r"
+	m.Payload.Technical.WhatWorkedOn = []string{"Shipped the thing."}
+	return m
+}
+
+func status(t *testing.T, rdb *redis.Client, id, sink string) Status {
+	t.Helper()
+	raw, err := rdb.HGet(context.Background(), "delivery:"+id, sink).Result()
+	if err != nil {
+		t.Fatalf("status %s: %v", sink, err)
+	}
+	var st Status
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		t.Fatalf("status %s: %v", sink, err)
+	}
+	return st
+}
+
+func TestWebhookSignature(t *testing.T) {
+	var got atomic.Int64
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get("X-Standup-Timestamp")
+		if want := "sha256=" + sign(testSecret, ts, body); r.Header.Get("X-Standup-Signature") != want {
+			t.Errorf("signature = %q, want %q", r.Header.Get("X-Standup-Signature"), want)
+		}
+		if r.Header.Get("X-Standup-Event") != "standup.published" {
+			t.Errorf("event = %q", r.Header.Get("X-Standup-Event"))
+		}
+		if r.Header.Get("X-Standup-Delivery") != "1700000000000-0" {
+			t.Errorf("delivery = %q", r.Header.Get("X-Standup-Delivery"))
+		}
+		var m Message
+		if err := json.Unmarshal(body, &m); err != nil || m.Repo != "o/r" {
+			t.Errorf("body = %s (%v)", body, err)
+		}
+		got.Add(1)
+	}))
+	defer srv.Close()
+
+	d, rdb := newTestDispatcher(t, srv)
+	d.Deliver(&Targets{Webhook: &WebhookTarget{URL: srv.URL + "/hook"}}, testMessage())
+	d.Close(5 * time.Second)
+
+	if got.Load() != 1 {
+		t.Fatalf("server got %d requests, want 1", got.Load())
+	}
+	if st := status(t, rdb, "1700000000000-0", "webhook"); st.State != StateDelivered || st.Attempts != 1 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestWebhookStatusCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		codes    []int
+		state    string
+		attempts int
+	}{
+		{"5xx is retried", []int{503, 502, 200}, StateDelivered, 3},
+		{"5xx gives up after max attempts", []int{500, 500, 500, 200}, StateFailed, 3},
+		{"408 is retried", []int{408, 200}, StateDelivered, 2},
+		{"4xx is permanent", []int{400, 200}, StateFailed, 1},
+		{"redirect is permanent", []int{302, 200}, StateFailed, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				if tt.codes[n-1] == 302 {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.codes[n-1])
+			}))
+			defer srv.Close()
+
+			d, rdb := newTestDispatcher(t, srv)
+			d.Deliver(&Targets{Webhook: &WebhookTarget{URL: srv.URL}}, testMessage())
+			d.Close(5 * time.Second)
+
+			st := status(t, rdb, "1700000000000-0", "webhook")
+			if st.State != tt.state || st.Attempts != tt.attempts {
+				t.Fatalf("status = %+v, want state %s after %d attempts", st, tt.state, tt.attempts)
+			}
+			if int(calls.Load()) != tt.attempts {
+				t.Fatalf("server got %d requests, want %d", calls.Load(), tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		n := len(times)
+		mu.Unlock()
+		if n == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	d, rdb := newTestDispatcher(t, srv)
+	d.cfg.DeliveryBackoffMax = 5 * time.Second
+	d.Deliver(&Targets{Slack: &SlackTarget{WebhookURL: srv.URL}}, testMessage())
+	d.Close(10 * time.Second)
+
+	if st := status(t, rdb, "1700000000000-0", "slack"); st.State != StateDelivered || st.Attempts != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if gap := times[1].Sub(times[0]); gap < time.Second {
+		t.Fatalf("retried after %v, want at least the 1s Retry-After", gap)
+	}
+}
+
+func TestStatusPerSink(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slack" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d, rdb := newTestDispatcher(t, srv)
+	d.Deliver(&Targets{
+		Webhook: &WebhookTarget{URL: srv.URL + "/hook"},
+		Slack:   &SlackTarget{WebhookURL: srv.URL + "/slack"},
+		Email:   &EmailTarget{To: []string{"a@example.com"}},
+	}, testMessage())
+	d.Close(5 * time.Second)
+
+	ctx := context.Background()
+	fields, err := rdb.HGetAll(ctx, "delivery:1700000000000-0").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 3 {
+		t.Fatalf("status fields = %v, want webhook, slack and email", fields)
+	}
+	host := strings.TrimPrefix(srv.URL, "https://")
+	if st := status(t, rdb, "1700000000000-0", "webhook"); st.State != StateDelivered || st.Target != host {
+		t.Errorf("webhook status = %+v", st)
+	}
+	if st := status(t, rdb, "1700000000000-0", "slack"); st.State != StateFailed || st.Target != host {
+		t.Errorf("slack status = %+v", st)
+	}
+	// No SMTP server is configured.
+	if st := status(t, rdb, "1700000000000-0", "email"); st.State != StateFailed || st.Attempts != 1 {
+		t.Errorf("email status = %+v", st)
+	}
+	if ttl := rdb.TTL(ctx, "delivery:1700000000000-0").Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("ttl = %v, want up to DeliveryStatusTTL", ttl)
+	}
+}
+
+func TestRejectedURL(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	d, rdb := newTestDispatcher(t, srv)
+	d.checkURL = validateURL
+	d.Deliver(&Targets{Webhook: &WebhookTarget{URL: srv.URL}}, testMessage())
+	d.Close(5 * time.Second)
+
+	if calls.Load() != 0 {
+		t.Fatalf("server got %d requests for a refused URL", calls.Load())
+	}
+	if st := status(t, rdb, "1700000000000-0", "webhook"); st.State != StateFailed || st.Attempts != 1 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		private bool
+		ok      bool
+	}{
+		{"https://hooks.example.com/x", false, true},
+		{"https://93.184.215.14/x", false, true},
+		{"http://hooks.example.com/x", false, false},
+		{"/relative", false, false},
+		{"https://localhost/x", true, false},
+		{"https://api.localhost:8443/x", true, false},
+		{"https://127.0.0.1/x", true, false},
+		{"https://10.1.2.3/x", true, false},
+		{"https://172.16.0.1/x", true, false},
+		{"https://192.168.1.1/x", true, false},
+		{"https://169.254.169.254/latest/meta-data", true, false},
+		{"https://100.64.0.1/x", true, false},
+		{"https://0.0.0.0/x", true, false},
+		{"https://[::1]/x", true, false},
+		{"https://[fd00::1]/x", true, false},
+		{"https://[fe80::1]/x", true, false},
+		{"https://[::ffff:127.0.0.1]/x", true, false},
+	}
+	for _, tt := range tests {
+		err := validateURL(tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("validateURL(%q) = %v, want ok=%v", tt.url, err, tt.ok)
+		}
+		if errors.Is(err, errPrivateAddress) != tt.private {
+			t.Errorf("validateURL(%q) = %v, want private=%v", tt.url, err, tt.private)
+		}
+	}
+}
+
+// TestDialRefusesPrivateAddress skips the URL check to show the dialer
+// still refuses a target that resolves to loopback.
+func TestDialRefusesPrivateAddress(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	d, rdb := newTestDispatcher(t, nil)
+	d.Deliver(&Targets{Slack: &SlackTarget{WebhookURL: strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)}}, testMessage())
+	d.Close(5 * time.Second)
+
+	if calls.Load() != 0 {
+		t.Fatalf("server got %d requests from a refused dial", calls.Load())
+	}
+	st := status(t, rdb, "1700000000000-0", "slack")
+	if st.State != StateFailed || st.Attempts != 1 || !strings.Contains(st.Error, errPrivateAddress.Error()) {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+// smtpSession is what fakeSMTP saw in one conversation.
+type smtpSession struct {
+	from string
+	rcpt []string
+	data string
+}
+
+// fakeSMTP accepts one plaintext SMTP conversation on a loopback port and
+// sends what it saw once the client quits.
+func fakeSMTP(t *testing.T) (string, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var s smtpSession
+		tp.PrintfLine("220 test ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 test")
+			case "MAIL":
+				s.from = arg
+				tp.PrintfLine("250 ok")
+			case "RCPT":
+				s.rcpt = append(s.rcpt, arg)
+				tp.PrintfLine("250 ok")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				b, err := io.ReadAll(bufio.NewReader(tp.DotReader()))
+				if err != nil {
+					return
+				}
+				s.data = string(b)
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				out <- s
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestEmailRoundTrip(t *testing.T) {
+	addr, sessions := fakeSMTP(t)
+	d, rdb := newTestDispatcher(t, nil)
+	d.cfg.SMTPAddr = addr
+	d.cfg.SMTPFrom = "Standups <standups@example.com>"
+	d.Deliver(&Targets{Email: &EmailTarget{
+		To:      []string{"Alice <alice@example.com>", "bob@example.com"},
+		Subject: "Daily standup",
+	}}, testMessage())
+	d.Close(5 * time.Second)
+
+	var s smtpSession
+	select {
+	case s = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no SMTP session")
+	}
+	if s.from != "FROM:<standups@example.com>" {
+		t.Errorf("MAIL %s", s.from)
+	}
+	if want := []string{"TO:<alice@example.com>", "TO:<bob@example.com>"}; strings.Join(s.rcpt, ",") != strings.Join(want, ",") {
+		t.Errorf("RCPT %v, want %v", s.rcpt, want)
+	}
+	for _, want := range []string{
+		`From: "Standups" <standups@example.com>`,
+		`To: "Alice" <alice@example.com>, <bob@example.com>`,
+		"Subject: Daily standup",
+		"Message-ID: <standup.1700000000000-0@example.com>",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/html; charset=utf-8",
+		"Shipped the thing.",
+	} {
+		if !strings.Contains(s.data, want) {
+			t.Errorf("message lacks %q:\n%s", want, s.data)
+		}
+	}
+	if st := status(t, rdb, "1700000000000-0", "email"); st.State != StateDelivered || st.Attempts != 1 {
+		t.Fatalf("status = %+v", st)
+	}
+}
@@ -0,0 +1,206 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/urizennnn/autostandup-reposcanner/render"
+)
+
+// smtpTimeout bounds a whole SMTP conversation.
+const smtpTimeout = 30 * time.Second
+
+// emailSink sends the Markdown and HTML renderings as a multipart/alternative
+// message. STARTTLS is used when the server offers it; credentials are only
+// sent over TLS or to localhost.
+type emailSink struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+	subject  string
+}
+
+func (s *emailSink) name() string   { return "email" }
+func (s *emailSink) target() string { return strings.Join(s.to, ",") }
+
+func (s *emailSink) send(ctx context.Context, m Message) error {
+	if s.addr == "" || s.from == "" {
+		return permanent(errors.New("APP_SMTP_ADDR and APP_SMTP_FROM must be set"))
+	}
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return permanent(fmt.Errorf("bad APP_SMTP_FROM: %w", err))
+	}
+	to := make([]*mail.Address, 0, len(s.to))
+	for _, raw := range s.to {
+		addr, err := mail.ParseAddress(raw)
+		if err != nil {
+			return permanent(fmt.Errorf("bad recipient %q: %w", raw, err))
+		}
+		to = append(to, addr)
+	}
+	msg, err := s.compose(m, from, to)
+	if err != nil {
+		return permanent(err)
+	}
+	host, _, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		return permanent(fmt.Errorf("bad smtp address: %w", err))
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return smtpError(err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return smtpError(err)
+		}
+	}
+	if s.username != "" {
+		// PlainAuth refuses to send credentials in the clear to anything
+		// but localhost. That will not change on a retry.
+		if _, isTLS := c.TLSConnectionState(); !isTLS && !isLocalhost(host) {
+			return permanent(fmt.Errorf("%s does not offer STARTTLS; refusing to send credentials unencrypted", s.addr))
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, host)); err != nil {
+			return smtpError(err)
+		}
+	}
+	// The envelope takes bare addresses; display names belong in the headers.
+	if err := c.Mail(from.Address); err != nil {
+		return smtpError(err)
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr.Address); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return smtpError(err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return smtpError(c.Quit())
+}
+
+// smtpError makes 5xx replies permanent; 4xx replies and connection errors
+// are retried.
+func smtpError(err error) error {
+	var te *textproto.Error
+	if errors.As(err, &te) && te.Code >= 500 {
+		return permanent(err)
+	}
+	return err
+}
+
+// isLocalhost mirrors the hosts smtp.PlainAuth accepts without TLS.
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func (s *emailSink) compose(m Message, from *mail.Address, to []*mail.Address) ([]byte, error) {
+	subject := s.subject
+	if subject == "" {
+		subject = m.Payload.Title
+	}
+	if subject == "" {
+		subject = "Standup for " + m.Repo
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType string
+		text        string
+	}{
+		{"text/plain; charset=utf-8", render.Markdown(m.Payload)},
+		{"text/html; charset=utf-8", "<!DOCTYPE html>\n<html><body>\n" + render.HTML(m.Payload) + "</body></html>\n"},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("composing email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(p.text)); err != nil {
+			return nil, fmt.Errorf("composing email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("composing email: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("composing email: %w", err)
+	}
+
+	rcpts := make([]string, len(to))
+	for i, addr := range to {
+		rcpts[i] = addr.String()
+	}
+	var msg bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&msg, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", strings.Join(rcpts, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(m.ID, from.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// messageID is stable for a result so a retried send that did reach the
+// server can be recognised as a duplicate.
+func messageID(id, from string) string {
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	if id == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	return "<standup." + id + "@" + domain + ">"
+}
@@ -0,0 +1,27 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/urizennnn/autostandup-reposcanner/render"
+)
+
+// slackSink posts the Block Kit rendering of the payload to an incoming
+// webhook.
+type slackSink struct {
+	url  string
+	http *http.Client
+}
+
+func (s *slackSink) name() string   { return "slack" }
+func (s *slackSink) target() string { return hostOf(s.url) }
+
+func (s *slackSink) send(ctx context.Context, m Message) error {
+	body, err := render.SlackBlocks(m.Payload)
+	if err != nil {
+		return permanent(fmt.Errorf("rendering slack message: %w", err))
+	}
+	return postJSON(ctx, s.http, s.url, body, nil)
+}
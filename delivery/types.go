@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"time"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// Targets are the places a job's result is sent after it is published.
+// Every field is optional.
+type Targets struct {
+	Webhook *WebhookTarget `json:"webhook,omitempty"`
+	Slack   *SlackTarget   `json:"slack,omitempty"`
+	Email   *EmailTarget   `json:"email,omitempty"`
+}
+
+// WebhookTarget receives the result as signed JSON.
+type WebhookTarget struct {
+	URL string `json:"url"`
+}
+
+// SlackTarget is a Slack incoming webhook.
+type SlackTarget struct {
+	WebhookURL string `json:"webhookUrl"`
+}
+
+// EmailTarget is sent through the configured SMTP server. Subject defaults
+// to the payload title.
+type EmailTarget struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject,omitempty"`
+}
+
+// Message is a published result as handed to the sinks. ID is the entry ID
+// in scan:results and identifies the delivery across retries.
+type Message struct {
+	ID            string            `json:"id"`
+	Repo          string            `json:"repo"`
+	From          string            `json:"from"`
+	To            string            `json:"to"`
+	Format        string            `json:"format"`
+	Language      string            `json:"language"`
+	IsTestStandup bool              `json:"isTestStandup,omitempty"`
+	Payload       ai.StandupPayload `json:"payload"`
+}
+
+const (
+	StateRetrying  = "retrying"
+	StateDelivered = "delivered"
+	StateFailed    = "failed"
+)
+
+// Status is the latest outcome of one sink for one result. Target names
+// where it went without the secret parts of webhook URLs.
+type Status struct {
+	Sink      string    `json:"sink"`
+	Target    string    `json:"target"`
+	State     string    `json:"state"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,107 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const userAgent = "autostandup-reposcanner"
+
+// webhookSink posts the message as JSON. X-Standup-Signature is
+// "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>" keyed with
+// APP_WEBHOOK_SECRET, where timestamp is X-Standup-Timestamp.
+type webhookSink struct {
+	url    string
+	secret string
+	http   *http.Client
+}
+
+func (s *webhookSink) name() string   { return "webhook" }
+func (s *webhookSink) target() string { return hostOf(s.url) }
+
+func (s *webhookSink) send(ctx context.Context, m Message) error {
+	if s.secret == "" {
+		return permanent(fmt.Errorf("%s is not set", SecretWebhookSecret))
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		return permanent(fmt.Errorf("encoding webhook body: %w", err))
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	return postJSON(ctx, s.http, s.url, body, map[string]string{
+		"X-Standup-Event":     "standup.published",
+		"X-Standup-Delivery":  m.ID,
+		"X-Standup-Timestamp": ts,
+		"X-Standup-Signature": "sha256=" + sign(s.secret, ts, body),
+	})
+}
+
+func sign(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// postJSON posts body to u. Timeouts, connection errors, 408, 429 and 5xx
+// are retried; other non-2xx responses, redirects included, and refused
+// private addresses are permanent.
+func postJSON(ctx context.Context, client *http.Client, u string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("building request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return permanent(err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("%s returned %d: %s", hostOf(u), resp.StatusCode, bytes.TrimSpace(snippet))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil {
+			return retryAfterError{err: err, after: time.Duration(secs) * time.Second}
+		}
+		return err
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		return err
+	default:
+		return permanent(err)
+	}
+}
+
+// hostOf names a webhook by its host only; the path of a Slack or chat
+// webhook is a credential.
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,240 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxBodyBytes caps the responses worth storing; larger ones pass through.
+// The API responses worth revalidating (commits, CODEOWNERS, branches) are
+// far smaller.
+const maxBodyBytes = 1 << 20
+
+// Entry is a stored GET response with the validators to revalidate it.
+type Entry struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+type Store interface {
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	Set(ctx context.Context, key string, e Entry) error
+}
+
+// Transport revalidates GET requests against stored responses. A stored
+// ETag or Last-Modified goes out as If-None-Match or If-Modified-Since, and a
+// 304 is answered from the store as a 200 carrying the fresh rate limit
+// headers. Scope keeps responses fetched with different credentials apart.
+type Transport struct {
+	Base  http.RoundTripper
+	Store Store
+	Scope string
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	key := t.key(req)
+	cached, ok, err := t.Store.Get(ctx, key)
+	if err != nil {
+		log.Printf("[WARN] etag store lookup: %v", err)
+	}
+
+	if ok {
+		req = req.Clone(ctx)
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return fromEntry(req, cached, resp.Header), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxBodyBytes {
+		// Too large to keep: replay what was read ahead of the rest.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := t.Store.Set(ctx, key, Entry{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	}); err != nil {
+		log.Printf("[WARN] etag store write: %v", err)
+	}
+	return resp, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) key(req *http.Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s", t.Scope, req.Header.Get("Accept"), req.URL.String())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fromEntry rebuilds a 200 from a stored entry, taking rate limit headers
+// from the 304 so callers see current quota.
+func fromEntry(req *http.Request, e Entry, fresh http.Header) *http.Response {
+	header := e.Header.Clone()
+	for k, v := range fresh {
+		if strings.HasPrefix(k, "X-Ratelimit-") || k == "Date" {
+			header[k] = v
+		}
+	}
+	header.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// MemoryStore keeps entries in a process-local LRU bounded both by entry
+// count and by the bytes the entries hold.
+type MemoryStore struct {
+	maxBytes int64
+
+	mu    sync.Mutex
+	lru   *simplelru.LRU[string, Entry]
+	bytes int64
+}
+
+func NewMemoryStore(size int, maxBytes int64) (*MemoryStore, error) {
+	s := &MemoryStore{maxBytes: maxBytes}
+	l, err := simplelru.NewLRU(size, func(_ string, e Entry) {
+		s.bytes -= e.size()
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.lru = l
+	return s, nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.lru.Get(key)
+	return e, ok, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, e Entry) error {
+	n := e.size()
+	if n > s.maxBytes {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lru.Remove(key)
+	s.lru.Add(key, e)
+	s.bytes += n
+	for s.bytes > s.maxBytes {
+		s.lru.RemoveOldest()
+	}
+	return nil
+}
+
+// size approximates the memory an entry holds.
+func (e Entry) size() int64 {
+	n := len(e.ETag) + len(e.LastModified) + len(e.Body)
+	for k, vs := range e.Header {
+		n += len(k)
+		for _, v := range vs {
+			n += len(v)
+		}
+	}
+	return int64(n)
+}
+
+// RedisStore shares entries between replicas and across restarts.
+type RedisStore struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewRedisStore(rdb *redis.Client, ttl time.Duration) *RedisStore {
+	return &RedisStore{rdb: rdb, ttl: ttl}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (Entry, bool, error) {
+	b, err := s.rdb.Get(ctx, "etag:"+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("reading etag entry: %w", err)
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return Entry{}, false, fmt.Errorf("decoding etag entry: %w", err)
+	}
+	return e, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding etag entry: %w", err)
+	}
+	return s.rdb.Set(ctx, "etag:"+key, b, s.ttl).Err()
+}
@@ -8,7 +8,10 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/urizennnn/autostandup-reposcanner/ai"
 	"github.com/urizennnn/autostandup-reposcanner/config"
+	"github.com/urizennnn/autostandup-reposcanner/delivery"
+	"github.com/urizennnn/autostandup-reposcanner/parser/github"
 	"github.com/urizennnn/autostandup-reposcanner/redis"
 )
 
@@ -20,13 +23,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("[FATAL] config error: %v", err)
 	}
+	if err := ai.LoadFormats(cfg.FormatsDir); err != nil {
+		log.Fatalf("[FATAL] formats: %v", err)
+	}
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	rdbClient, err := redis.ConnectToRedisURL(cfg.RedisURL, cfg.RedisConnTimeout)
 	if err != nil {
 		log.Fatalf("[FATAL] redis connection: %v", err)
 	}
-	err = redis.WatchStreams(ctx, rdbClient, "scan:jobs", "scanners", consumerName, &cfg)
+	if err := redis.PublishSchema(ctx, rdbClient); err != nil {
+		log.Printf("[WARN] publishing result schema: %v", err)
+	}
+	shared, err := github.NewShared(&cfg, rdbClient)
+	if err != nil {
+		log.Fatalf("[FATAL] shared state: %v", err)
+	}
+	defer shared.Close()
+	deliveries := delivery.NewDispatcher(&cfg, rdbClient)
+	defer deliveries.Close(cfg.ShutdownGrace)
+	err = redis.WatchStreams(ctx, rdbClient, "scan:jobs", "scanners", consumerName, &cfg, shared, deliveries)
 	if err != nil && ctx.Err() == nil {
 		log.Fatalf("[FATAL] watch streams: %v", err)
 	}
@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// scanBranches resolves the branches a request covers. The first entry is
+// the primary branch: the single requested branch, or the default branch.
+// Zero or one entries mean a plain single-branch scan.
+func (c *Client) scanBranches(ctx context.Context, req ScanRequest) ([]string, error) {
+	requested := dedupeBranches(append([]string{req.Branch}, req.Branches...))
+	if !req.AllBranches && len(requested) <= 1 {
+		return requested, nil
+	}
+
+	def, err := c.defaultBranch(ctx, req.Owner, req.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("resolving default branch: %w", err)
+	}
+
+	branches := requested
+	if req.AllBranches {
+		listed, err := c.listActiveBranches(ctx, req.Owner, req.Repo, req.Since)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, listed...)
+	}
+	// The default branch is always scanned so commits can be marked merged.
+	branches = dedupeBranches(append(branches, def))
+	if req.Branch == "" {
+		branches = dedupeBranches(append([]string{def}, branches...))
+	}
+
+	if len(branches) > c.config.MaxBranches {
+		kept := branches[:c.config.MaxBranches]
+		if !slices.Contains(kept, def) {
+			kept[len(kept)-1] = def
+		}
+		var dropped []string
+		for _, b := range branches {
+			if !slices.Contains(kept, b) {
+				dropped = append(dropped, b)
+			}
+		}
+		log.Printf("[WARN] %s/%s has %d branches to scan, keeping %d, dropped: %s",
+			req.Owner, req.Repo, len(branches), len(kept), strings.Join(dropped, ", "))
+		branches = kept
+	}
+	return branches, nil
+}
+
+// fetchBranchCommits lists commits on each branch, dedupes them by SHA and
+// annotates each with the branches it appears on. Commits missing from the
+// default branch are marked in progress. Commits keep the primary branch's
+// order first, then each other branch's in turn.
+func (c *Client) fetchBranchCommits(ctx context.Context, owner, repo string, branches []string, req ScanRequest, filter pathFilter) ([]ai.Commit, error) {
+	def, err := c.defaultBranch(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("resolving default branch: %w", err)
+	}
+
+	var listed []*github.RepositoryCommit
+	onBranches := map[string][]string{}
+	for _, b := range branches {
+		commits, err := c.listBranchCommits(ctx, owner, repo, b, req.Since, req.Until)
+		if err != nil {
+			log.Printf("[WARN] commits on %s in %s/%s: %v", b, owner, repo, err)
+			continue
+		}
+		for _, cm := range commits {
+			sha := cm.GetSHA()
+			if _, seen := onBranches[sha]; !seen {
+				listed = append(listed, cm)
+			}
+			onBranches[sha] = append(onBranches[sha], b)
+		}
+	}
+	if len(listed) == 0 {
+		log.Printf("[INFO] no commits found %s/%s branches=%d", owner, repo, len(branches))
+		return nil, nil
+	}
+
+	commits, err := c.buildCommits(ctx, owner, repo, listed, branches, req, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range commits {
+		commits[i].Branches = onBranches[commits[i].SHA]
+		commits[i].InProgress = !slices.Contains(commits[i].Branches, def)
+	}
+	return commits, nil
+}
+
+// activeBranchesQuery lists branches by their head commit's date, newest
+// first, so listing can stop at the first branch older than the window.
+const activeBranchesQuery = `query($owner: String!, $repo: String!, $after: String) {
+  repository(owner: $owner, name: $repo) {
+    refs(refPrefix: "refs/heads/", first: 100, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      nodes { name target { ... on Commit { committedDate } } }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+type activeBranchesResponse struct {
+	Data struct {
+		Repository struct {
+			Refs struct {
+				Nodes []struct {
+					Name   string `json:"name"`
+					Target struct {
+						CommittedDate time.Time `json:"committedDate"`
+					} `json:"target"`
+				} `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"refs"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// listActiveBranches returns the branches whose head commit is at or after
+// since, most recently committed first. Stale branches cannot hold commits
+// in the window, so they never take up room under MaxBranches.
+func (c *Client) listActiveBranches(ctx context.Context, owner, repo string, since time.Time) ([]string, error) {
+	vars := map[string]any{"owner": owner, "repo": repo}
+	var out []string
+	listed, stale := 0, 0
+	for {
+		var resp activeBranchesResponse
+		if err := c.graphql(ctx, activeBranchesQuery, vars, &resp); err != nil {
+			return nil, fmt.Errorf("listing branches: %w", err)
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("listing branches: graphql: %s", resp.Errors[0].Message)
+		}
+		refs := resp.Data.Repository.Refs
+		for _, n := range refs.Nodes {
+			listed++
+			// Heads that are not commits have no date; keep them rather than
+			// guess.
+			if d := n.Target.CommittedDate; !d.IsZero() && d.Before(since) {
+				stale++
+				continue
+			}
+			out = append(out, n.Name)
+		}
+		// Ordered by date, so once a page ends in stale branches the rest
+		// are stale too.
+		if !refs.PageInfo.HasNextPage || stale > 0 {
+			break
+		}
+		vars["after"] = refs.PageInfo.EndCursor
+	}
+	log.Printf("[INFO] %s/%s branches listed=%d active_since_window=%d", owner, repo, listed, len(out))
+	return out, nil
+}
+
+func dedupeBranches(in []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(in))
+	for _, b := range in {
+		b = strings.TrimSpace(b)
+		if b == "" || seen[b] {
+			continue
+		}
+		seen[b] = true
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// ciLookback caps how many older commits are checked to find when a red
+// branch head first went red.
+const ciLookback = 5
+
+var failingConclusions = map[string]bool{
+	"failure":         true,
+	"timed_out":       true,
+	"cancelled":       true,
+	"action_required": true,
+	"startup_failure": true,
+}
+
+// ciStatus reports CI on the newest commit in the window and, when enabled,
+// on each merge commit. commits must be newest first, as GitHub lists them.
+// In a multi-branch scan only commits on branch are considered.
+func (c *Client) ciStatus(ctx context.Context, owner, repo, branch string, commits []ai.Commit) (*ai.CIStatus, error) {
+	ref := branch
+	if ref == "" {
+		var err error
+		if ref, err = c.defaultBranch(ctx, owner, repo); err != nil {
+			log.Printf("[WARN] default branch %s/%s: %v", owner, repo, err)
+		}
+	}
+
+	onRef := commits[:0:0]
+	for _, cm := range commits {
+		if cm.Branches == nil || slices.Contains(cm.Branches, ref) {
+			onRef = append(onRef, cm)
+		}
+	}
+	commits = onRef
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	head, err := c.commitCI(ctx, owner, repo, commits[0].SHA)
+	if err != nil {
+		return nil, err
+	}
+	head.Ref = ref
+
+	if head.State == "failure" {
+		head.RedSince = earliestFailure(head.Failing)
+		for i := 1; i < len(commits) && i <= ciLookback; i++ {
+			prev, err := c.commitCI(ctx, owner, repo, commits[i].SHA)
+			if err != nil || prev.State != "failure" {
+				break
+			}
+			if t := earliestFailure(prev.Failing); t != nil {
+				head.RedSince = t
+			}
+		}
+	}
+
+	if c.config.CIMergeCommits {
+		for _, cm := range commits[1:] {
+			if !cm.Merge {
+				continue
+			}
+			st, err := c.commitCI(ctx, owner, repo, cm.SHA)
+			if err != nil {
+				log.Printf("[WARN] ci status %s: %v", cm.SHA, err)
+				continue
+			}
+			if st.State != "" {
+				head.Merges = append(head.Merges, st)
+			}
+		}
+	}
+
+	if head.State == "" && len(head.Merges) == 0 {
+		return nil, nil
+	}
+	return &head, nil
+}
+
+// commitCI merges the legacy commit statuses and the check runs on sha into
+// one state. A commit nothing reports on has an empty state.
+func (c *Client) commitCI(ctx context.Context, owner, repo, sha string) (ai.CIStatus, error) {
+	st := ai.CIStatus{SHA: sha}
+	var pending, reported bool
+
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return st, err
+	}
+	combined, _, err := c.gh.Repositories.GetCombinedStatus(ctx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return st, fmt.Errorf("fetching combined status: %w", err)
+	}
+	for _, s := range combined.Statuses {
+		reported = true
+		switch s.GetState() {
+		case "failure", "error":
+			st.Failing = append(st.Failing, ai.FailingCheck{
+				Name:        s.GetContext(),
+				Conclusion:  s.GetState(),
+				URL:         s.GetTargetURL(),
+				CompletedAt: s.GetUpdatedAt().Time,
+			})
+		case "pending":
+			pending = true
+		}
+	}
+
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return st, err
+	}
+	runs, _, err := c.gh.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return st, fmt.Errorf("listing check runs: %w", err)
+	}
+	for _, r := range runs.CheckRuns {
+		reported = true
+		if r.GetStatus() != "completed" {
+			pending = true
+			continue
+		}
+		if failingConclusions[r.GetConclusion()] {
+			st.Failing = append(st.Failing, ai.FailingCheck{
+				Name:        r.GetName(),
+				Conclusion:  r.GetConclusion(),
+				URL:         r.GetHTMLURL(),
+				CompletedAt: r.GetCompletedAt().Time,
+			})
+		}
+	}
+
+	switch {
+	case len(st.Failing) > 0:
+		st.State = "failure"
+	case pending:
+		st.State = "pending"
+	case reported:
+		st.State = "success"
+	}
+	return st, nil
+}
+
+func (c *Client) defaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	cacheKey := fmt.Sprintf("default-branch:%s:%s", owner, repo)
+	return c.shared.defaultBranches.GetOrLoad(ctx, cacheKey, time.Hour, func() (string, error) {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return "", err
+		}
+		r, _, err := c.gh.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return "", err
+		}
+		return r.GetDefaultBranch(), nil
+	})
+}
+
+func earliestFailure(checks []ai.FailingCheck) *time.Time {
+	var out *time.Time
+	for _, f := range checks {
+		if f.CompletedAt.IsZero() {
+			continue
+		}
+		if out == nil || f.CompletedAt.Before(*out) {
+			t := f.CompletedAt
+			out = &t
+		}
+	}
+	return out
+}
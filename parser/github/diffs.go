@@ -0,0 +1,54 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// minDiffBytes is the smallest patch excerpt worth sending; below it the
+// budget is treated as spent.
+const minDiffBytes = 200
+
+// attachDiffs fills each commit's Diff from its file patches, newest commit
+// first, until the job's byte budget runs out. Files keep rejects and files
+// without a patch (binaries, very large diffs) are left out. Each patch
+// is cut at a line boundary to at most perFile bytes.
+func attachDiffs(commits []ai.Commit, budget, perFile int, keep pathFilter) {
+	for i := range commits {
+		for _, f := range commits[i].FileChanges {
+			if budget < minDiffBytes {
+				return
+			}
+			if f.Patch == "" || !keep.matches(f.Path) {
+				continue
+			}
+			patch, truncated := truncatePatch(f.Patch, min(perFile, budget))
+			if patch == "" {
+				continue
+			}
+			budget -= len(patch)
+			commits[i].Diff = append(commits[i].Diff, ai.FileChange{
+				Path:         f.Path,
+				PreviousPath: f.PreviousPath,
+				Status:       f.Status,
+				Additions:    f.Additions,
+				Deletions:    f.Deletions,
+				Patch:        patch,
+				Truncated:    truncated,
+			})
+		}
+	}
+}
+
+// truncatePatch keeps whole lines of patch up to n bytes.
+func truncatePatch(patch string, n int) (string, bool) {
+	if len(patch) <= n {
+		return patch, false
+	}
+	cut := strings.LastIndex(patch[:n], "\n")
+	if cut <= 0 {
+		return "", true
+	}
+	return patch[:cut], true
+}
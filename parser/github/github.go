@@ -4,40 +4,40 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/google/go-github/v74/github"
 	"github.com/jferrl/go-githubauth"
+	"github.com/redis/go-redis/v9"
 	"github.com/urizennnn/autostandup-reposcanner/ai"
-	"github.com/urizennnn/autostandup-reposcanner/cache"
 	"github.com/urizennnn/autostandup-reposcanner/config"
+	"github.com/urizennnn/autostandup-reposcanner/httpcache"
 	"github.com/urizennnn/autostandup-reposcanner/ratelimit"
 	"golang.org/x/oauth2"
-	"golang.org/x/sync/errgroup"
 )
 
-func NewClient(cfg *config.Config, privateKey []byte, clientID string, installationID int64) (*Client, error) {
+func NewClient(cfg *config.Config, rdb *redis.Client, shared *Shared, privateKey []byte, clientID string, installationID int64) (*Client, error) {
 	limiter := ratelimit.New(cfg.GithubRateLimit, cfg.OpenaiRateLimit)
-	c, err := cache.New(cfg.CacheSize)
-	if err != nil {
-		return nil, fmt.Errorf("creating cache: %w", err)
-	}
-
-	ghClient, err := createGithubClient(privateKey, clientID, installationID, cfg.HTTPClientTimeout)
+	ghClient, err := createGithubClient(privateKey, clientID, installationID, cfg.HTTPClientTimeout, shared.etags)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		gh:      ghClient,
-		limiter: limiter,
-		cache:   c,
-		config:  cfg,
+		gh:        ghClient,
+		limiter:   limiter,
+		shared:    shared,
+		config:    cfg,
+		summaries: ai.NewResultCache(rdb, cfg.SummaryCacheTTL),
+		budget:    ai.NewBudget(rdb, cfg.PricingTable, cfg.InstallationDailyBudget, cfg.GlobalDailyBudget),
+		identity:  newIdentityResolver(cfg.AuthorAliases, cfg.BotEmails),
+
+		installationID: installationID,
 	}, nil
 }
 
-func createGithubClient(privateKey []byte, clientID string, installationID int64, timeout time.Duration) (*github.Client, error) {
+func createGithubClient(privateKey []byte, clientID string, installationID int64, timeout time.Duration, etags httpcache.Store) (*github.Client, error) {
 	log.Printf("[INFO] creating github client for installation %d", installationID)
 	appTokenSource, err := githubauth.NewApplicationTokenSource(clientID, privateKey)
 	if err != nil {
@@ -47,136 +47,373 @@ func createGithubClient(privateKey []byte, clientID string, installationID int64
 
 	baseClient := oauth2.NewClient(context.Background(), installationTokenSource)
 	baseClient.Timeout = timeout
+	if etags != nil {
+		// Installations see different data, so their responses are kept apart.
+		baseClient.Transport = &httpcache.Transport{
+			Base:  baseClient.Transport,
+			Store: etags,
+			Scope: strconv.FormatInt(installationID, 10),
+		}
+	}
 
 	client := github.NewClient(baseClient)
 	return client, nil
 }
 
-func (c *Client) ListCommits(ctx context.Context, owner, repo, branch, format string, since, until time.Time) (ai.SummarizeResult, error) {
-	log.Printf("[INFO] fetching commits %s/%s branch=%s", owner, repo, branch)
-	commits, _, err := c.gh.Repositories.ListCommits(
-		ctx, owner, repo, &github.CommitsListOptions{
-			Since: since,
-			Until: until,
-			SHA:   branch,
-		})
+func (c *Client) ListCommits(ctx context.Context, req ScanRequest) (ai.SummarizeResult, error) {
+	owner, repo := req.Owner, req.Repo
+	formatType, err := ai.ResolveFormat(req.Format)
+	if err != nil {
+		return ai.SummarizeResult{}, err
+	}
+
+	branches, err := c.scanBranches(ctx, req)
+	if err != nil {
+		return ai.SummarizeResult{}, err
+	}
+
+	filter := newPathFilter(req.IncludePaths, req.ExcludePaths)
+	var aiCommits []ai.Commit
+	var branch string
+	if len(branches) > 0 {
+		branch = branches[0]
+	}
+	if len(branches) > 1 {
+		aiCommits, err = c.fetchBranchCommits(ctx, owner, repo, branches, req, filter)
+	} else {
+		aiCommits, err = c.fetchCommits(ctx, owner, repo, branch, req, filter)
+	}
 	if err != nil {
-		return ai.SummarizeResult{}, fmt.Errorf("fetching commits: %w", err)
+		return ai.SummarizeResult{}, err
 	}
 
+	// CI is read off the branch head before bots and contributor filters
+	// narrow the commit list.
+	var ci *ai.CIStatus
+	if formatType == ai.FormatTechnical {
+		if ci, err = c.ciStatus(ctx, owner, repo, branch, aiCommits); err != nil {
+			log.Printf("[WARN] ci status %s/%s: %v", owner, repo, err)
+		}
+	}
+
+	// Release commits are often made by bots, so tags are matched against
+	// every commit in the window.
+	releases, err := c.listReleases(ctx, owner, repo, req.Since, req.Until, aiCommits)
+	if err != nil {
+		log.Printf("[WARN] releases %s/%s: %v", owner, repo, err)
+	}
+
+	// CI and releases describe the whole branch; everything after this only
+	// sees commits touching the filtered paths.
+	if filter.active() {
+		aiCommits = touchingPaths(aiCommits)
+	}
+
+	aiCommits, botCommits := c.identity.splitBots(aiCommits)
+	if c.config.BotCommits == "exclude" {
+		botCommits = nil
+	}
+
+	pullRequests, err := c.listPullRequests(ctx, owner, repo, req.Since, req.Until)
+	if err != nil {
+		log.Printf("[WARN] pull requests %s/%s: %v", owner, repo, err)
+	}
+
+	if req.Contributor != "" {
+		aiCommits = ai.FilterByContributor(aiCommits, req.Contributor)
+		botCommits = ai.FilterByContributor(botCommits, req.Contributor)
+		pullRequests = ai.FilterPullRequests(pullRequests, req.Contributor)
+	}
+
+	issues := c.collectIssues(ctx, owner, repo, req.Since, req.Until, aiCommits, pullRequests)
+
+	if req.IncludeDiffs {
+		attachDiffs(aiCommits, c.config.DiffBudgetBytes, c.config.DiffFileBytes, newPathFilter(nil, c.config.DiffSkipPaths))
+	}
+
+	if len(aiCommits) == 0 && len(pullRequests) == 0 {
+		log.Printf("[INFO] no human activity found %s/%s bots=%d releases=%d", owner, repo, len(botCommits), len(releases))
+		if len(botCommits) == 0 && len(releases) == 0 {
+			return ai.SummarizeResult{}, nil
+		}
+		return ai.SummarizeResult{Payload: ai.StandupPayload{
+			Repo:     owner + "/" + repo,
+			Releases: releases,
+			Bots:     ai.SummarizeBots(botCommits),
+		}}, nil
+	}
+
+	openaiAPIKey, err := config.FetchSecretByName("APP_OPENAI_API_KEY")
+	if err != nil {
+		return ai.SummarizeResult{}, fmt.Errorf("fetching openai api key: %w", err)
+	}
+
+	job := ai.SummarizeJob{
+		Repo:         owner + "/" + repo,
+		ProjectName:  repo,
+		Handle:       owner,
+		Since:        req.Since.UTC(),
+		Until:        req.Until.UTC(),
+		Language:     req.Language,
+		Mode:         req.Mode,
+		Commits:      aiCommits,
+		PullRequests: pullRequests,
+		Issues:       issues,
+		CI:           ci,
+		Releases:     releases,
+	}
+	if req.Mode == ai.ModePerContributor {
+		job.Contributors = ai.FilterActivity(ai.GroupByContributor(aiCommits), req.Contributor)
+	}
+	if len(aiCommits) > 0 {
+		if hasFileChanges(aiCommits) {
+			job.Components = c.components(ctx, owner, repo, branch, aiCommits)
+		}
+		files := ai.SummarizeFiles(aiCommits)
+		job.FilesChanged = &files
+	}
+
+	res, err := c.summarize(ctx, openaiAPIKey, job, formatType, req.ForceRefresh)
+	if err != nil {
+		return ai.SummarizeResult{}, err
+	}
+	res.Payload.Bots = ai.SummarizeBots(botCommits)
+	return res, nil
+}
+
+func (c *Client) fetchCommits(ctx context.Context, owner, repo, branch string, req ScanRequest, filter pathFilter) ([]ai.Commit, error) {
+	commits, err := c.listBranchCommits(ctx, owner, repo, branch, req.Since, req.Until)
+	if err != nil {
+		return nil, err
+	}
 	if len(commits) == 0 {
 		log.Printf("[INFO] no commits found %s/%s", owner, repo)
-		return ai.SummarizeResult{}, nil
+		return nil, nil
+	}
+	return c.buildCommits(ctx, owner, repo, commits, []string{branch}, req, filter)
+}
+
+// listBranchCommits lists every commit on branch in the window, 100 per page.
+func (c *Client) listBranchCommits(ctx context.Context, owner, repo, branch string, since, until time.Time) ([]*github.RepositoryCommit, error) {
+	log.Printf("[INFO] fetching commits %s/%s branch=%s", owner, repo, branch)
+	opts := &github.CommitsListOptions{
+		Since:       since,
+		Until:       until,
+		SHA:         branch,
+		ListOptions: github.ListOptions{PerPage: 100},
 	}
 
-	results := make([]ai.Commit, len(commits))
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(c.config.GithubConcurrency)
+	var out []*github.RepositoryCommit
+	for {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return nil, err
+		}
+		commits, resp, err := c.gh.Repositories.ListCommits(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("fetching commits: %w", err)
+		}
+		out = append(out, commits...)
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// buildCommits fetches stats for the listed commits and converts them,
+// keeping the listing order. Stats only count files that pass filter; commits
+// without stats are dropped. If any commit only has totals, as the GraphQL
+// source returns, every commit's FileChanges is left empty so the file
+// breakdowns are skipped as a whole rather than computed from a subset.
+func (c *Client) buildCommits(ctx context.Context, owner, repo string, commits []*github.RepositoryCommit, branches []string, req ScanRequest, filter pathFilter) ([]ai.Commit, error) {
+	q := statsQuery{Branches: branches, Since: req.Since, Until: req.Until, Filter: filter, Patches: req.IncludeDiffs}
+	for _, commit := range commits {
+		if commit != nil {
+			q.SHAs = append(q.SHAs, commit.GetSHA())
+		}
+	}
+
+	fetcher := c.statsSource(len(q.SHAs), filter.active() || req.IncludeDiffs)
+	stats, err := fetcher.fetchStats(ctx, owner, repo, q)
+	if err != nil {
+		return nil, err
+	}
+	graphqlCalls, restCalls := fetcher.calls()
+	log.Printf("[INFO] commit stats %s/%s commits=%d graphql_requests=%d rest_requests=%d", owner, repo, len(q.SHAs), graphqlCalls, restCalls)
 
-	for i, commit := range commits {
+	aiCommits := make([]ai.Commit, 0, len(commits))
+	detailed := true
+	for _, commit := range commits {
 		if commit == nil {
 			continue
 		}
-		i, commit := i, commit
-		g.Go(func() error {
-			sha := commit.GetSHA()
-			name := commit.GetCommit().GetAuthor().GetName()
-			email := commit.GetCommit().GetAuthor().GetEmail()
-			if email == "" {
-				email = commit.GetCommit().GetCommitter().GetEmail()
-			}
-			if name == "" {
-				name = commit.GetCommit().GetCommitter().GetName()
-			}
-			msg := commit.GetCommit().GetMessage()
-
-			files, adds, dels, err := c.getCommitStats(ctx, owner, repo, sha)
-			if err != nil {
-				log.Printf("[WARN] commit stats error %s: %v", sha, err)
-				return nil
-			}
-
-			results[i] = ai.Commit{
-				SHA:         sha,
-				AuthorName:  name,
-				AuthorEmail: email,
-				Message:     msg,
-				Files:       files,
-				Additions:   adds,
-				Deletions:   dels,
-			}
-			return nil
+		sha := commit.GetSHA()
+		st, ok := stats[sha]
+		if !ok {
+			continue
+		}
+		if st.Files == nil && (st.Changed > 0 || st.Additions+st.Deletions > 0) {
+			detailed = false
+		}
+		login := commit.GetAuthor().GetLogin()
+		name := commit.GetCommit().GetAuthor().GetName()
+		email := commit.GetCommit().GetAuthor().GetEmail()
+		if email == "" {
+			email = commit.GetCommit().GetCommitter().GetEmail()
+		}
+		if name == "" {
+			name = commit.GetCommit().GetCommitter().GetName()
+		}
+		rawMsg := commit.GetCommit().GetMessage()
+		msg, trailers := parseTrailers(rawMsg)
+
+		aiCommits = append(aiCommits, ai.Commit{
+			SHA:         sha,
+			AuthorName:  name,
+			AuthorEmail: email,
+			AuthorLogin: login,
+			Message:     msg,
+			Files:       st.Changed,
+			Additions:   st.Additions,
+			Deletions:   st.Deletions,
+			FileChanges: st.Files,
+			Date:        commit.GetCommit().GetCommitter().GetDate().Time,
+			CoAuthors:   trailers.coAuthors,
+			SignedOffBy: trailers.signedOffBy,
+			ReviewedBy:  trailers.reviewedBy,
+			IssueRefs:   parseIssueRefs(rawMsg, owner, repo),
+			Merge:       len(commit.Parents) > 1,
 		})
 	}
+	if !detailed {
+		for i := range aiCommits {
+			aiCommits[i].FileChanges = nil
+		}
+		log.Printf("[INFO] commit stats %s/%s are totals only, skipping file breakdowns", owner, repo)
+	}
+	return aiCommits, nil
+}
 
-	if err := g.Wait(); err != nil {
-		return ai.SummarizeResult{}, err
+func (c *Client) summarize(ctx context.Context, apiKey string, job ai.SummarizeJob, format ai.FormatType, forceRefresh bool) (ai.SummarizeResult, error) {
+	primary := c.config.OpenaiModel
+
+	if !forceRefresh {
+		if res, ok := c.cachedSummary(ctx, ai.SummaryKey(job, format, primary)); ok {
+			return res, nil
+		}
 	}
 
-	aiCommits := make([]ai.Commit, 0, len(results))
-	for _, r := range results {
-		if r.SHA != "" {
-			aiCommits = append(aiCommits, r)
+	plan, err := c.budget.Plan(ctx, c.installationID, job, format, primary, c.config.OpenaiFallbackModel)
+	if err != nil {
+		log.Printf("[WARN] budget check %s: %v", job.Repo, err)
+	}
+	if plan.Decision != "" {
+		log.Printf("[WARN] budget decision=%s installation=%d repo=%s model=%s", plan.Decision, c.installationID, job.Repo, plan.Model)
+	}
+
+	if plan.Decision == ai.BudgetHeuristic {
+		res := ai.SummarizeHeuristic(job, format)
+		res.Details.RequestedModel = primary
+		res.Details.BudgetDecision = plan.Decision
+		return res, nil
+	}
+
+	key := ai.SummaryKey(job, format, plan.Model)
+	if plan.Model != primary && !forceRefresh {
+		if res, ok := c.cachedSummary(ctx, key); ok {
+			res.Details.RequestedModel = primary
+			res.Details.BudgetDecision = plan.Decision
+			return res, nil
 		}
 	}
 
-	openaiAPIKey, err := config.FetchSecretByName("APP_OPENAI_API_KEY")
+	res, err := ai.SummarizeWithFallback(ctx, apiKey, plan.Model, c.config.PricingTable, job, format)
 	if err != nil {
-		return ai.SummarizeResult{}, fmt.Errorf("fetching openai api key: %w", err)
+		return ai.SummarizeResult{}, err
 	}
+	res.Details.RequestedModel = primary
+	res.Details.BudgetDecision = plan.Decision
 
-	job := ai.SummarizeJob{
-		Repo:        owner + "/" + repo,
-		ProjectName: repo,
-		Handle:      owner,
-		Since:       since.UTC(),
-		Until:       until.UTC(),
-		Commits:     aiCommits,
-	}
-
-	var formatType ai.FormatType
-	switch strings.ToUpper(strings.ReplaceAll(format, "-", "_")) {
-	case "TECHNICAL":
-		formatType = ai.FormatTechnical
-	case "MILDLY_TECHNICAL":
-		formatType = ai.FormatMildlyTechnical
-	case "LAYMAN":
-		formatType = ai.FormatLayman
-	default:
-		log.Printf("[WARN] unknown format: %s, defaulting to technical", format)
-		formatType = ai.FormatTechnical
-	}
-
-	return ai.Summarize(ctx, openaiAPIKey, job, formatType)
+	if err := c.budget.Record(ctx, c.installationID, res.Details.EstimatedCost); err != nil {
+		log.Printf("[WARN] budget record %s: %v", job.Repo, err)
+	}
+
+	// Heuristic output stands in for an outage; the next run should try the model again.
+	if res.Details.Model != ai.HeuristicModel {
+		if err := c.summaries.Set(ctx, key, res); err != nil {
+			log.Printf("[WARN] summary cache store %s: %v", job.Repo, err)
+		}
+	}
+	return res, nil
 }
 
-func (c *Client) getCommitStats(ctx context.Context, owner, repo, sha string) (files int, additions int, deletions int, err error) {
+func (c *Client) cachedSummary(ctx context.Context, key string) (ai.SummarizeResult, bool) {
+	res, ok, err := c.summaries.Get(ctx, key)
+	if err != nil {
+		log.Printf("[WARN] summary cache lookup %s: %v", key, err)
+	}
+	if ok {
+		log.Printf("[INFO] summary cache hit key=%s", key)
+	}
+	return res, ok
+}
+
+// getCommitStats returns the files sha changed that pass filter. With
+// patches set, an entry that came back from the second tier without its
+// patches is fetched again. fetched reports whether a request was made.
+func (c *Client) getCommitStats(ctx context.Context, owner, repo, sha string, filter pathFilter, patches bool) (changes []ai.FileChange, fetched bool, err error) {
 	cacheKey := fmt.Sprintf("commit:%s:%s:%s", owner, repo, sha)
 
-	if cached, ok := c.cache.Get(cacheKey); ok {
-		stats := cached.(commitStats)
-		return stats.Files, stats.Additions, stats.Deletions, nil
+	stats, err := c.shared.commits.GetOrLoad(ctx, cacheKey, time.Hour, func() (commitStats, error) {
+		fetched = true
+		return c.fetchCommitStats(ctx, owner, repo, sha)
+	})
+	if err == nil && patches && stats.NoPatches {
+		fetched = true
+		if stats, err = c.fetchCommitStats(ctx, owner, repo, sha); err == nil {
+			c.shared.commits.Set(cacheKey, stats, time.Hour)
+		}
+	}
+	if err != nil {
+		return nil, fetched, err
+	}
+
+	if !filter.active() {
+		return stats.Files, fetched, nil
 	}
+	for _, f := range stats.Files {
+		if filter.matches(f.Path) {
+			changes = append(changes, f)
+		}
+	}
+	return changes, fetched, nil
+}
 
+func (c *Client) fetchCommitStats(ctx context.Context, owner, repo, sha string) (commitStats, error) {
+	var stats commitStats
 	if err := c.limiter.WaitGithub(ctx); err != nil {
-		return 0, 0, 0, err
+		return stats, err
 	}
 
 	commit, _, err := c.gh.Repositories.GetCommit(ctx, owner, repo, sha, &github.ListOptions{})
 	if err != nil {
-		return 0, 0, 0, err
+		return stats, err
 	}
 
-	var stats commitStats
 	for _, f := range commit.Files {
 		if f == nil {
 			continue
 		}
-		stats.Files++
-		stats.Additions += f.GetAdditions()
-		stats.Deletions += f.GetDeletions()
+		stats.Files = append(stats.Files, ai.FileChange{
+			Path:         f.GetFilename(),
+			PreviousPath: f.GetPreviousFilename(),
+			Status:       f.GetStatus(),
+			Additions:    f.GetAdditions(),
+			Deletions:    f.GetDeletions(),
+			Patch:        f.GetPatch(),
+			Language:     ai.FileLanguage(f.GetFilename()),
+			Category:     ai.FileCategory(f.GetFilename()),
+		})
 	}
-
-	c.cache.Set(cacheKey, stats, time.Hour)
-	return stats.Files, stats.Additions, stats.Deletions, nil
+	return stats, nil
 }
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+var noreplyRe = regexp.MustCompile(`^(?:\d+\+)?([a-z0-9-]+(?:\[bot\])?)@users\.noreply\.github\.com$`)
+
+var knownBotEmails = []string{
+	"bot@renovateapp.com",
+	"support@dependabot.com",
+	"action@github.com",
+	"41898282+github-actions[bot]@users.noreply.github.com",
+	"49699333+dependabot[bot]@users.noreply.github.com",
+	"29139614+renovate[bot]@users.noreply.github.com",
+}
+
+type identityResolver struct {
+	aliases   map[string]string
+	botEmails map[string]bool
+}
+
+func newIdentityResolver(aliases map[string]string, extraBotEmails []string) *identityResolver {
+	r := &identityResolver{
+		aliases:   make(map[string]string, len(aliases)),
+		botEmails: map[string]bool{},
+	}
+	for k, v := range aliases {
+		r.aliases[strings.ToLower(strings.TrimSpace(k))] = strings.TrimPrefix(strings.TrimSpace(v), "@")
+	}
+	for _, e := range knownBotEmails {
+		r.botEmails[e] = true
+	}
+	for _, e := range extraBotEmails {
+		r.botEmails[strings.ToLower(strings.TrimSpace(e))] = true
+	}
+	return r
+}
+
+// resolve settles the login of the commit's author and co-authors.
+func (r *identityResolver) resolve(c *ai.Commit) {
+	c.AuthorLogin = r.login(c.AuthorLogin, c.AuthorEmail, c.AuthorName)
+	for i := range c.CoAuthors {
+		p := &c.CoAuthors[i]
+		p.Login = r.login(p.Login, p.Email, p.Name)
+	}
+}
+
+// login picks, in order: an alias for the login, email or name, the linked
+// GitHub account, then a login recovered from a users.noreply.github.com
+// address.
+func (r *identityResolver) login(login, email, name string) string {
+	for _, k := range []string{login, email, name} {
+		if alias, ok := r.aliases[strings.ToLower(k)]; ok && k != "" {
+			return alias
+		}
+	}
+	if login != "" {
+		return login
+	}
+	if m := noreplyRe.FindStringSubmatch(strings.ToLower(email)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func (r *identityResolver) isBot(c ai.Commit) bool {
+	email := strings.ToLower(c.AuthorEmail)
+	return strings.HasSuffix(strings.ToLower(c.AuthorLogin), "[bot]") ||
+		strings.HasSuffix(strings.ToLower(c.AuthorName), "[bot]") ||
+		strings.Contains(email, "[bot]@") ||
+		r.botEmails[email]
+}
+
+// splitBots resolves identities and separates bot commits from human ones.
+func (r *identityResolver) splitBots(commits []ai.Commit) (humans, bots []ai.Commit) {
+	for _, c := range commits {
+		r.resolve(&c)
+		if r.isBot(c) {
+			bots = append(bots, c)
+			continue
+		}
+		humans = append(humans, c)
+	}
+	return humans, bots
+}
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// issueRefRe matches "#45", "fixes #45" and "owner/repo#45". The optional
+// closing keyword is captured so the summary can tell fixes from mentions.
+var issueRefRe = regexp.MustCompile(`(?i)(?:\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+)?(?:\b([\w.-]+/[\w.-]+))?#(\d+)\b`)
+
+const issueCacheTTL = 10 * time.Minute
+
+// parseIssueRefs returns references to issues in owner/repo, ignoring those
+// that point at other repositories.
+func parseIssueRefs(text, owner, repo string) []ai.IssueRef {
+	var out []ai.IssueRef
+	seen := map[int]int{}
+	for _, m := range issueRefRe.FindAllStringSubmatch(text, -1) {
+		if m[2] != "" && !strings.EqualFold(m[2], owner+"/"+repo) {
+			continue
+		}
+		n, err := strconv.Atoi(m[3])
+		if err != nil || n <= 0 {
+			continue
+		}
+		closing := m[1] != ""
+		if i, ok := seen[n]; ok {
+			out[i].Closing = out[i].Closing || closing
+			continue
+		}
+		seen[n] = len(out)
+		out = append(out, ai.IssueRef{Number: n, Closing: closing})
+	}
+	return out
+}
+
+// collectIssues resolves issues referenced by commits and pull requests and
+// merges them with issues opened or closed in the window.
+func (c *Client) collectIssues(ctx context.Context, owner, repo string, since, until time.Time, commits []ai.Commit, prs []ai.PullRequest) []ai.Issue {
+	byNumber := map[int]*ai.Issue{}
+	var order []int
+	add := func(issue ai.Issue) *ai.Issue {
+		if existing, ok := byNumber[issue.Number]; ok {
+			return existing
+		}
+		byNumber[issue.Number] = &issue
+		order = append(order, issue.Number)
+		return &issue
+	}
+
+	active, err := c.listActiveIssues(ctx, owner, repo, since, until)
+	if err != nil {
+		log.Printf("[WARN] listing active issues %s/%s: %v", owner, repo, err)
+	}
+	for _, issue := range active {
+		add(issue)
+	}
+
+	prNumbers := map[int]bool{}
+	for _, pr := range prs {
+		prNumbers[pr.Number] = true
+	}
+
+	referencedBy := func(source string, r ai.IssueRef) {
+		if prNumbers[r.Number] {
+			return
+		}
+		issue, ok := byNumber[r.Number]
+		if !ok {
+			if len(order) >= c.config.MaxIssues {
+				return
+			}
+			fetched, err := c.getIssue(ctx, owner, repo, r.Number)
+			if err != nil {
+				log.Printf("[WARN] resolving issue #%d in %s/%s: %v", r.Number, owner, repo, err)
+				return
+			}
+			if fetched.IsPullRequest {
+				return
+			}
+			issue = add(fetched)
+		}
+		issue.ReferencedBy = append(issue.ReferencedBy, source)
+		issue.Closing = issue.Closing || r.Closing
+	}
+
+	for _, cm := range commits {
+		for _, r := range cm.IssueRefs {
+			referencedBy(shortSHA(cm.SHA), r)
+		}
+	}
+	for _, pr := range prs {
+		for _, r := range pr.IssueRefs {
+			referencedBy(fmt.Sprintf("#%d", pr.Number), r)
+		}
+	}
+
+	out := make([]ai.Issue, 0, len(order))
+	for _, n := range order {
+		out = append(out, *byNumber[n])
+	}
+	return out
+}
+
+func (c *Client) listActiveIssues(ctx context.Context, owner, repo string, since, until time.Time) ([]ai.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var out []ai.Issue
+	for {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return nil, err
+		}
+		page, resp, err := c.gh.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing issues: %w", err)
+		}
+		for _, is := range page {
+			if is.IsPullRequest() {
+				continue
+			}
+			issue := toIssue(is)
+			if t := is.GetCreatedAt().Time; inWindow(t, since, until) {
+				issue.Events = append(issue.Events, ai.IssueEvent{Type: "opened", At: t})
+			}
+			if t := is.GetClosedAt().Time; inWindow(t, since, until) {
+				issue.Events = append(issue.Events, ai.IssueEvent{Type: "closed", At: t, By: is.GetClosedBy().GetLogin()})
+			}
+			if len(issue.Events) > 0 {
+				out = append(out, issue)
+			}
+		}
+		if resp.NextPage == 0 || len(out) >= c.config.MaxIssues {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	if len(out) > c.config.MaxIssues {
+		out = out[:c.config.MaxIssues]
+	}
+	return out, nil
+}
+
+func (c *Client) getIssue(ctx context.Context, owner, repo string, number int) (ai.Issue, error) {
+	cacheKey := fmt.Sprintf("issue:%s:%s:%d", owner, repo, number)
+	return c.shared.issues.GetOrLoad(ctx, cacheKey, issueCacheTTL, func() (ai.Issue, error) {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return ai.Issue{}, err
+		}
+		is, _, err := c.gh.Issues.Get(ctx, owner, repo, number)
+		if err != nil {
+			return ai.Issue{}, err
+		}
+		return toIssue(is), nil
+	})
+}
+
+func toIssue(is *github.Issue) ai.Issue {
+	issue := ai.Issue{
+		Number:        is.GetNumber(),
+		Title:         is.GetTitle(),
+		State:         is.GetState(),
+		Author:        is.GetUser().GetLogin(),
+		IsPullRequest: is.IsPullRequest(),
+	}
+	for _, l := range is.Labels {
+		issue.Labels = append(issue.Labels, l.GetName())
+	}
+	sort.Strings(issue.Labels)
+	return issue
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
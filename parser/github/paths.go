@@ -0,0 +1,260 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// codeownersPaths are the locations GitHub reads CODEOWNERS from, in order.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+const codeownersCacheTTL = time.Hour
+
+// pathFilter keeps files matching any include glob (or every file when there
+// are none) and no exclude glob. Globs match the whole repository path; "**"
+// spans directories and a glob naming a directory, with or without a
+// trailing "/", matches everything below.
+type pathFilter struct {
+	include []string
+	exclude []string
+}
+
+func newPathFilter(include, exclude []string) pathFilter {
+	return pathFilter{include: cleanGlobs(include), exclude: cleanGlobs(exclude)}
+}
+
+func (f pathFilter) active() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+func (f pathFilter) matches(p string) bool {
+	p = cleanPath(p)
+	if len(f.include) > 0 && !matchAny(f.include, p) {
+		return false
+	}
+	return !matchAny(f.exclude, p)
+}
+
+// ValidatePathFilter reports the first malformed glob in include or exclude.
+func ValidatePathFilter(include, exclude []string) error {
+	for _, g := range append(cleanGlobs(include), cleanGlobs(exclude)...) {
+		for _, seg := range strings.Split(g, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return fmt.Errorf("bad path glob %q: %w", g, err)
+			}
+		}
+	}
+	return nil
+}
+
+// touchingPaths drops commits that no longer have any files after the path
+// filter was applied to their stats.
+func touchingPaths(commits []ai.Commit) []ai.Commit {
+	out := make([]ai.Commit, 0, len(commits))
+	for _, c := range commits {
+		if len(c.FileChanges) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func cleanGlobs(globs []string) []string {
+	var out []string
+	for _, g := range globs {
+		g = strings.TrimPrefix(strings.TrimSpace(g), "./")
+		g = strings.TrimPrefix(g, "/")
+		if g == "" {
+			continue
+		}
+		if strings.HasSuffix(g, "/") {
+			g += "**"
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+func cleanPath(p string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(p, "./"), "/")
+}
+
+// matchAny reports whether p matches any glob. As in CODEOWNERS, a glob
+// naming a directory also covers everything below it.
+func matchAny(globs []string, p string) bool {
+	for _, g := range globs {
+		if matchGlob(g, p) || matchGlob(g+"/**", p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated path segment by segment with
+// path.Match, letting a "**" segment stand for any number of directories.
+func matchGlob(glob, p string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(glob, p []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(p); i++ {
+				if matchSegments(glob[1:], p[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(p) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(glob[0], p[0]); !ok {
+			return false
+		}
+		glob, p = glob[1:], p[1:]
+	}
+	return len(p) == 0
+}
+
+type codeownersRule struct {
+	glob   string
+	owners []string
+}
+
+// codeowners is a parsed CODEOWNERS file. As on GitHub, the last matching
+// rule wins.
+type codeowners []codeownersRule
+
+func parseCodeowners(text string) codeowners {
+	var out codeowners
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		pattern := fields[0]
+
+		anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+		glob := strings.TrimPrefix(pattern, "/")
+		if strings.HasSuffix(glob, "/") {
+			glob += "**"
+		}
+		if !anchored {
+			glob = "**/" + glob
+		}
+		out = append(out, codeownersRule{glob: glob, owners: fields[1:]})
+	}
+	return out
+}
+
+func (co codeowners) owners(p string) []string {
+	for i := len(co) - 1; i >= 0; i-- {
+		r := co[i]
+		// A rule naming a directory covers everything below it.
+		if matchGlob(r.glob, p) || matchGlob(r.glob+"/**", p) {
+			return r.owners
+		}
+	}
+	return nil
+}
+
+// getCodeowners fetches the repository's CODEOWNERS file at ref. A missing
+// file is not an error.
+func (c *Client) getCodeowners(ctx context.Context, owner, repo, ref string) (codeowners, error) {
+	cacheKey := fmt.Sprintf("codeowners:%s:%s:%s", owner, repo, ref)
+	return c.shared.codeowners.GetOrLoad(ctx, cacheKey, codeownersCacheTTL, func() (codeowners, error) {
+		for _, p := range codeownersPaths {
+			if err := c.limiter.WaitGithub(ctx); err != nil {
+				return nil, err
+			}
+			file, _, resp, err := c.gh.Repositories.GetContents(ctx, owner, repo, p, &github.RepositoryContentGetOptions{Ref: ref})
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("fetching %s: %w", p, err)
+			}
+			if file == nil {
+				continue
+			}
+			text, err := file.GetContent()
+			if err != nil {
+				return nil, fmt.Errorf("decoding %s: %w", p, err)
+			}
+			return parseCodeowners(text), nil
+		}
+		return nil, nil
+	})
+}
+
+// components attributes each commit's files to the CODEOWNERS owners of the
+// file or, without an owner, to its top-level directory. Components are
+// ordered by lines changed.
+func (c *Client) components(ctx context.Context, owner, repo, ref string, commits []ai.Commit) []ai.Component {
+	rules, err := c.getCodeowners(ctx, owner, repo, ref)
+	if err != nil {
+		log.Printf("[WARN] codeowners %s/%s: %v", owner, repo, err)
+	}
+
+	index := map[string]int{}
+	var out []ai.Component
+	for _, cm := range commits {
+		touched := map[int]bool{}
+		for _, f := range cm.FileChanges {
+			name, kind := topLevelDir(f.Path), ai.ComponentDirectory
+			if owners := rules.owners(f.Path); len(owners) > 0 {
+				name, kind = strings.Join(owners, " "), ai.ComponentTeam
+			}
+			key := kind + ":" + name
+			i, ok := index[key]
+			if !ok {
+				i = len(out)
+				index[key] = i
+				out = append(out, ai.Component{Name: name, Kind: kind})
+			}
+			out[i].FilesChanged.Files++
+			out[i].FilesChanged.Additions += f.Additions
+			out[i].FilesChanged.Deletions += f.Deletions
+			if !touched[i] {
+				touched[i] = true
+				out[i].Commits++
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].FilesChanged, out[j].FilesChanged
+		return a.Additions+a.Deletions > b.Additions+b.Deletions
+	})
+	return out
+}
+
+// hasFileChanges reports whether any commit lists its files, which the
+// breakdowns need. Totals-only stats list none.
+func hasFileChanges(commits []ai.Commit) bool {
+	for _, cm := range commits {
+		if len(cm.FileChanges) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func topLevelDir(p string) string {
+	p = cleanPath(p)
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[:i]
+	}
+	return "/"
+}
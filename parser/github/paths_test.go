@@ -0,0 +1,29 @@
+package github
+
+import "testing"
+
+func TestPathFilterMatches(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		path             string
+		want             bool
+	}{
+		{nil, nil, "main.go", true},
+		{[]string{"docs"}, nil, "docs/x.md", true},
+		{[]string{"docs"}, nil, "docs", true},
+		{[]string{"docs"}, nil, "docsite/x.md", false},
+		{[]string{"services/payments"}, nil, "services/payments/api/h.go", true},
+		{[]string{"services/payments/"}, nil, "services/payments/h.go", true},
+		{[]string{"services/*"}, nil, "services/payments/h.go", true},
+		{[]string{"**/*.go"}, nil, "a/b/c.go", true},
+		{[]string{"**/*.go"}, nil, "a/b/c.md", false},
+		{nil, []string{"vendor"}, "vendor/x/y.go", false},
+		{[]string{"./src/"}, []string{"src/gen"}, "src/gen/a.go", false},
+	}
+	for _, tt := range tests {
+		f := newPathFilter(tt.include, tt.exclude)
+		if got := f.matches(tt.path); got != tt.want {
+			t.Errorf("include=%q exclude=%q matches(%q) = %v, want %v", tt.include, tt.exclude, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// listPullRequests returns pull requests with activity in [since, until]: opened,
+// merged or closed in the window, or reviewed in it. GitHub has no server-side
+// filter for this, so PRs are read newest-updated first until they fall out of
+// the window. MaxPullRequests caps the PRs returned, not the ones read: for a
+// past window, PRs updated after it come first and must not use up the cap.
+func (c *Client) listPullRequests(ctx context.Context, owner, repo string, since, until time.Time) ([]ai.PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var out []ai.PullRequest
+	for {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return nil, err
+		}
+		page, resp, err := c.gh.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing pull requests: %w", err)
+		}
+
+		for _, pr := range page {
+			if pr.GetUpdatedAt().Before(since) {
+				return out, nil
+			}
+			// Opened after the window, so nothing in it can involve this PR;
+			// skip it without asking for its reviews.
+			if pr.GetCreatedAt().After(until) {
+				continue
+			}
+			p := newPullRequest(pr, owner, repo, since, until)
+			reviews, err := c.listReviews(ctx, owner, repo, pr.GetNumber(), since, until)
+			if err != nil {
+				return nil, err
+			}
+			p.Reviews = reviews
+
+			if len(p.Events) == 0 && len(p.Reviews) == 0 {
+				continue
+			}
+			out = append(out, p)
+			if len(out) >= c.config.MaxPullRequests {
+				return out, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// newPullRequest describes pr with the state transitions that fall in the
+// window. Reviews are filled in by the caller.
+func newPullRequest(pr *github.PullRequest, owner, repo string, since, until time.Time) ai.PullRequest {
+	p := ai.PullRequest{
+		Number: pr.GetNumber(),
+		Title:  pr.GetTitle(),
+		Author: pr.GetUser().GetLogin(),
+		State:  pr.GetState(),
+		Draft:  pr.GetDraft(),
+		Base:   pr.GetBase().GetRef(),
+		Head:   pr.GetHead().GetRef(),
+
+		IssueRefs: parseIssueRefs(pr.GetTitle()+"\n"+pr.GetBody(), owner, repo),
+	}
+	if pr.MergedAt != nil {
+		p.State = "merged"
+	}
+	for _, r := range pr.RequestedReviewers {
+		p.RequestedReviewers = append(p.RequestedReviewers, r.GetLogin())
+	}
+
+	if t := pr.GetCreatedAt().Time; inWindow(t, since, until) {
+		p.Events = append(p.Events, ai.PullRequestEvent{Type: "opened", At: t})
+	}
+	if t := pr.GetMergedAt().Time; inWindow(t, since, until) {
+		p.Events = append(p.Events, ai.PullRequestEvent{Type: "merged", At: t, By: pr.GetMergedBy().GetLogin()})
+	} else if t := pr.GetClosedAt().Time; pr.MergedAt == nil && inWindow(t, since, until) {
+		p.Events = append(p.Events, ai.PullRequestEvent{Type: "closed", At: t})
+	}
+	return p
+}
+
+func (c *Client) listReviews(ctx context.Context, owner, repo string, number int, since, until time.Time) ([]ai.Review, error) {
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return nil, err
+	}
+	reviews, _, err := c.gh.PullRequests.ListReviews(ctx, owner, repo, number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("listing reviews for #%d: %w", number, err)
+	}
+
+	var out []ai.Review
+	for _, r := range reviews {
+		t := r.GetSubmittedAt().Time
+		if !inWindow(t, since, until) || r.GetState() == "PENDING" {
+			continue
+		}
+		out = append(out, ai.Review{
+			Reviewer:    r.GetUser().GetLogin(),
+			State:       strings.ToLower(r.GetState()),
+			SubmittedAt: t,
+		})
+	}
+	return out, nil
+}
+
+func inWindow(t, since, until time.Time) bool {
+	return !t.IsZero() && !t.Before(since) && !t.After(until)
+}
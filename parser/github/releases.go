@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// releaseNotesLimit keeps long changelogs from crowding the prompt.
+const releaseNotesLimit = 2000
+
+// listReleases returns releases published in [since, until] and tags whose
+// commit landed in the window, oldest first. Each carries the commit range
+// since the release or tag before it.
+func (c *Client) listReleases(ctx context.Context, owner, repo string, since, until time.Time, commits []ai.Commit) ([]ai.Release, error) {
+	published, err := c.publishedReleases(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := map[string]bool{}
+	for _, r := range published {
+		tagged[r.Tag] = true
+	}
+	tags, err := c.windowTags(ctx, owner, repo, commits)
+	if err != nil {
+		log.Printf("[WARN] listing tags %s/%s: %v", owner, repo, err)
+	}
+	for _, t := range tags {
+		if !tagged[t.Tag] {
+			published = append(published, t)
+		}
+	}
+
+	// Newest first, so the entry after each one is the release it follows.
+	sort.SliceStable(published, func(i, j int) bool {
+		return published[i].At.After(published[j].At)
+	})
+
+	var out []ai.Release
+	for i, r := range published {
+		if !inWindow(r.At, since, until) {
+			continue
+		}
+		if i+1 < len(published) {
+			r.Range = c.releaseRange(ctx, owner, repo, published[i+1].Tag, r.Tag)
+		}
+		out = append(out, r)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].At.Before(out[j].At)
+	})
+	return out, nil
+}
+
+// publishedReleases lists non-draft releases newest first, down to and
+// including the first one published before since.
+func (c *Client) publishedReleases(ctx context.Context, owner, repo string, since time.Time) ([]ai.Release, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var out []ai.Release
+	for {
+		if err := c.limiter.WaitGithub(ctx); err != nil {
+			return nil, err
+		}
+		page, resp, err := c.gh.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing releases: %w", err)
+		}
+		for _, r := range page {
+			if r.GetDraft() {
+				continue
+			}
+			at := r.GetPublishedAt().Time
+			if at.IsZero() {
+				at = r.GetCreatedAt().Time
+			}
+			out = append(out, ai.Release{
+				Tag:        r.GetTagName(),
+				Name:       r.GetName(),
+				Notes:      truncate(r.GetBody(), releaseNotesLimit),
+				Author:     r.GetAuthor().GetLogin(),
+				Prerelease: r.GetPrerelease(),
+				At:         at,
+				URL:        r.GetHTMLURL(),
+			})
+			if at.Before(since) {
+				return out, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// windowTags returns tags pointing at commits in the window. Tags carry no
+// creation date of their own, so the tagged commit's date stands in for it.
+func (c *Client) windowTags(ctx context.Context, owner, repo string, commits []ai.Commit) ([]ai.Release, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	dates := make(map[string]time.Time, len(commits))
+	for _, cm := range commits {
+		dates[cm.SHA] = cm.Date
+	}
+
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return nil, err
+	}
+	tags, _, err := c.gh.Repositories.ListTags(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ai.Release
+	for _, t := range tags {
+		at, ok := dates[t.GetCommit().GetSHA()]
+		if !ok {
+			continue
+		}
+		out = append(out, ai.Release{Tag: t.GetName(), At: at})
+	}
+	return out, nil
+}
+
+func (c *Client) releaseRange(ctx context.Context, owner, repo, base, head string) *ai.ReleaseRange {
+	rng := &ai.ReleaseRange{From: base, To: head}
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return rng
+	}
+	cmp, _, err := c.gh.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		log.Printf("[WARN] comparing %s...%s in %s/%s: %v", base, head, owner, repo, err)
+		return rng
+	}
+	rng.Commits = cmp.GetTotalCommits()
+	rng.URL = cmp.GetHTMLURL()
+	return rng
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
@@ -0,0 +1,126 @@
+package github
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+	"github.com/urizennnn/autostandup-reposcanner/cache"
+	"github.com/urizennnn/autostandup-reposcanner/config"
+	"github.com/urizennnn/autostandup-reposcanner/httpcache"
+)
+
+// Shared holds state that outlives a job. Clients are built per job, so
+// anything worth reusing across scans lives here and is created once per
+// process.
+type Shared struct {
+	etags httpcache.Store
+
+	commits         *cache.Cache[string, commitStats]
+	issues          *cache.Cache[string, ai.Issue]
+	codeowners      *cache.Cache[string, codeowners]
+	defaultBranches *cache.Cache[string, string]
+}
+
+func NewShared(cfg *config.Config, rdb *redis.Client) (*Shared, error) {
+	s := &Shared{}
+	switch cfg.EtagStore {
+	case "memory":
+		store, err := httpcache.NewMemoryStore(cfg.EtagCacheSize, cfg.EtagCacheMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("creating etag store: %w", err)
+		}
+		s.etags = store
+	case "redis":
+		s.etags = httpcache.NewRedisStore(rdb, cfg.EtagCacheTTL)
+	}
+
+	var err error
+	// Stats for a SHA never change, so they can outlive the process.
+	commitOpts := cacheOptions[commitStats](cfg)
+	switch cfg.CommitCacheStore {
+	case "redis":
+		commitOpts.L2 = cache.NewRedisTier(rdb, "cache:")
+	case "disk":
+		tier, err := cache.NewDiskTier(cfg.CommitCacheDir, cfg.CommitCacheMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("opening commit cache: %w", err)
+		}
+		commitOpts.L2 = tier
+	}
+	if commitOpts.L2 != nil {
+		commitOpts.Codec = commitCodec{}
+		commitOpts.L2TTL = cfg.CommitCacheTTL
+	}
+	if s.commits, err = cache.New(commitOpts); err != nil {
+		return nil, fmt.Errorf("creating commit cache: %w", err)
+	}
+	if s.issues, err = newCache[ai.Issue](cfg); err != nil {
+		return nil, fmt.Errorf("creating issue cache: %w", err)
+	}
+	if s.codeowners, err = newCache[codeowners](cfg); err != nil {
+		return nil, fmt.Errorf("creating codeowners cache: %w", err)
+	}
+	if s.defaultBranches, err = newCache[string](cfg); err != nil {
+		return nil, fmt.Errorf("creating default branch cache: %w", err)
+	}
+	return s, nil
+}
+
+// commitCodec stores commit stats without their patches, which make up most
+// of an entry and are only needed by jobs asking for diffs. Those refetch
+// the commit when they read an entry marked NoPatches.
+type commitCodec struct {
+	cache.JSONCodec[commitStats]
+}
+
+func (c commitCodec) Encode(st commitStats) ([]byte, error) {
+	if st.Files != nil {
+		files := make([]ai.FileChange, len(st.Files))
+		for i, f := range st.Files {
+			if f.Patch != "" {
+				f.Patch = ""
+				st.NoPatches = true
+			}
+			files[i] = f
+		}
+		st.Files = files
+	}
+	return c.JSONCodec.Encode(st)
+}
+
+func newCache[V any](cfg *config.Config) (*cache.Cache[string, V], error) {
+	return cache.New(cacheOptions[V](cfg))
+}
+
+func cacheOptions[V any](cfg *config.Config) cache.Options[string, V] {
+	return cache.Options[string, V]{
+		Size:            cfg.CacheSize,
+		JanitorInterval: cfg.CacheSweepInterval,
+	}
+}
+
+type sharedCache interface {
+	Stats() cache.Stats
+	Close()
+}
+
+// Close stops the caches' janitors and logs their counters.
+func (s *Shared) Close() {
+	caches := []struct {
+		name  string
+		cache sharedCache
+	}{
+		{"commits", s.commits},
+		{"issues", s.issues},
+		{"codeowners", s.codeowners},
+		{"default-branches", s.defaultBranches},
+	}
+	for _, c := range caches {
+		c.cache.Close()
+		st := c.cache.Stats()
+		log.Printf("[INFO] cache %s hits=%d misses=%d evictions=%d expirations=%d len=%d l2_hits=%d l2_misses=%d",
+			c.name, st.Hits, st.Misses, st.Evictions, st.Expirations, st.Len, st.L2Hits, st.L2Misses)
+	}
+}
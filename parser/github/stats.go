@@ -0,0 +1,269 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	StatsSourceAuto    = "auto"
+	StatsSourceREST    = "rest"
+	StatsSourceGraphQL = "graphql"
+)
+
+// statsQuery describes the commits a job needs stats for. Branches and the
+// window let a batched source walk history instead of looking up each SHA.
+type statsQuery struct {
+	SHAs     []string
+	Branches []string
+	Since    time.Time
+	Until    time.Time
+	Filter   pathFilter
+	// Patches asks for each file's patch, for diff excerpts.
+	Patches bool
+}
+
+// statsFetcher returns stats for the queried commits, keyed by SHA. Commits
+// it could not resolve are missing from the map.
+type statsFetcher interface {
+	fetchStats(ctx context.Context, owner, repo string, q statsQuery) (map[string]commitStats, error)
+	// calls reports the GraphQL and REST requests made so far.
+	calls() (graphql, rest int64)
+}
+
+// statsSource picks the stats fetcher for a job. GraphQL only returns totals,
+// so jobs that need each commit's files always use REST, and it is only used
+// at all when APP_COMMIT_STATS_SOURCE opts into it: the default, rest, keeps
+// every job's breakdowns.
+func (c *Client) statsSource(commits int, needFiles bool) statsFetcher {
+	rest := &restStats{c: c}
+	switch {
+	case needFiles, c.config.CommitStatsSource == StatsSourceREST:
+		return rest
+	case c.config.CommitStatsSource == StatsSourceAuto && commits < c.config.GraphqlMinCommits:
+		return rest
+	default:
+		return &graphqlStats{c: c, rest: rest}
+	}
+}
+
+// restStats makes one Repositories.GetCommit call per uncached commit and
+// returns every file it changed.
+type restStats struct {
+	c        *Client
+	requests atomic.Int64
+}
+
+func (s *restStats) fetchStats(ctx context.Context, owner, repo string, q statsQuery) (map[string]commitStats, error) {
+	out := make(map[string]commitStats, len(q.SHAs))
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.c.config.GithubConcurrency)
+	for _, sha := range q.SHAs {
+		g.Go(func() error {
+			changes, fetched, err := s.c.getCommitStats(gctx, owner, repo, sha, q.Filter, q.Patches)
+			if fetched {
+				s.requests.Add(1)
+			}
+			if err != nil {
+				log.Printf("[WARN] commit stats error %s: %v", sha, err)
+				return nil
+			}
+			mu.Lock()
+			out[sha] = commitStats{Files: changes}.totals()
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *restStats) calls() (int64, int64) {
+	return 0, s.requests.Load()
+}
+
+// graphqlStats walks each branch's history over GraphQL, 100 commits per
+// request, reading additions, deletions and changedFilesIfAvailable. Commits
+// it does not reach, and every commit when GraphQL fails, go to REST.
+type graphqlStats struct {
+	c        *Client
+	rest     *restStats
+	requests atomic.Int64
+}
+
+func (s *graphqlStats) fetchStats(ctx context.Context, owner, repo string, q statsQuery) (map[string]commitStats, error) {
+	out := make(map[string]commitStats, len(q.SHAs))
+	wanted := make(map[string]bool, len(q.SHAs))
+	for _, sha := range q.SHAs {
+		// Full REST entries already carry the totals.
+		if st, ok := s.c.cachedCommitTotals(ctx, owner, repo, sha); ok {
+			out[sha] = st
+			continue
+		}
+		wanted[sha] = true
+	}
+
+	branches := q.Branches
+	if len(branches) == 0 {
+		branches = []string{""}
+	}
+	for _, b := range branches {
+		if len(wanted) == 0 {
+			break
+		}
+		if err := s.walkHistory(ctx, owner, repo, b, q.Since, q.Until, wanted, out); err != nil {
+			log.Printf("[WARN] graphql history %s/%s branch=%s, falling back to REST: %v", owner, repo, b, err)
+			break
+		}
+	}
+
+	if len(wanted) == 0 {
+		return out, nil
+	}
+	rest := q
+	rest.SHAs = rest.SHAs[:0:0]
+	for _, sha := range q.SHAs {
+		if wanted[sha] {
+			rest.SHAs = append(rest.SHAs, sha)
+		}
+	}
+	fallback, err := s.rest.fetchStats(ctx, owner, repo, rest)
+	if err != nil {
+		return nil, err
+	}
+	for sha, st := range fallback {
+		out[sha] = st
+	}
+	return out, nil
+}
+
+func (s *graphqlStats) calls() (int64, int64) {
+	_, rest := s.rest.calls()
+	return s.requests.Load(), rest
+}
+
+// historyQuery reads one page of up to 100 commits, the most GitHub allows.
+const historyQuery = `query($owner: String!, $repo: String!, $ref: String!, $since: GitTimestamp, $until: GitTimestamp, $after: String) {
+  repository(owner: $owner, name: $repo) {
+    object(expression: $ref) {
+      ... on Commit {
+        history(first: 100, since: $since, until: $until, after: $after) {
+          nodes { oid additions deletions changedFilesIfAvailable }
+          pageInfo { hasNextPage endCursor }
+        }
+      }
+    }
+  }
+}`
+
+type historyResponse struct {
+	Data struct {
+		Repository struct {
+			Object *struct {
+				History struct {
+					Nodes []struct {
+						OID                     string `json:"oid"`
+						Additions               int    `json:"additions"`
+						Deletions               int    `json:"deletions"`
+						ChangedFilesIfAvailable *int   `json:"changedFilesIfAvailable"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"history"`
+			} `json:"object"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// walkHistory pages through ref's history in the window, moving each wanted
+// commit it meets from wanted into out.
+func (s *graphqlStats) walkHistory(ctx context.Context, owner, repo, ref string, since, until time.Time, wanted map[string]bool, out map[string]commitStats) error {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	vars := map[string]any{
+		"owner": owner,
+		"repo":  repo,
+		"ref":   ref,
+		"since": since.UTC().Format(time.RFC3339),
+		"until": until.UTC().Format(time.RFC3339),
+	}
+
+	for len(wanted) > 0 {
+		var resp historyResponse
+		if err := s.c.graphql(ctx, historyQuery, vars, &resp); err != nil {
+			return err
+		}
+		s.requests.Add(1)
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+		}
+		obj := resp.Data.Repository.Object
+		if obj == nil {
+			return fmt.Errorf("ref %q not found", ref)
+		}
+
+		for _, n := range obj.History.Nodes {
+			if !wanted[n.OID] {
+				continue
+			}
+			st := commitStats{Additions: n.Additions, Deletions: n.Deletions}
+			if n.ChangedFilesIfAvailable != nil {
+				st.Changed = *n.ChangedFilesIfAvailable
+			}
+			out[n.OID] = st
+			delete(wanted, n.OID)
+		}
+		if !obj.History.PageInfo.HasNextPage {
+			return nil
+		}
+		vars["after"] = obj.History.PageInfo.EndCursor
+	}
+	return nil
+}
+
+// graphql posts a query to the GraphQL endpoint next to the REST base URL,
+// which is /graphql on github.com and /api/graphql on GitHub Enterprise.
+func (c *Client) graphql(ctx context.Context, query string, vars map[string]any, v any) error {
+	if err := c.limiter.WaitGithub(ctx); err != nil {
+		return err
+	}
+	endpoint := "graphql"
+	if strings.HasSuffix(c.gh.BaseURL.Path, "/api/v3/") {
+		endpoint = "../graphql"
+	}
+	req, err := c.gh.NewRequest("POST", endpoint, map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("building graphql request: %w", err)
+	}
+	if _, err := c.gh.Do(ctx, req, v); err != nil {
+		return fmt.Errorf("graphql request: %w", err)
+	}
+	return nil
+}
+
+// cachedCommitTotals returns the totals of a commit already in the stats
+// cache, without a request.
+func (c *Client) cachedCommitTotals(ctx context.Context, owner, repo, sha string) (commitStats, bool) {
+	st, ok := c.shared.commits.Lookup(ctx, fmt.Sprintf("commit:%s:%s:%s", owner, repo, sha), time.Hour)
+	if !ok {
+		return commitStats{}, false
+	}
+	return st.totals(), true
+}
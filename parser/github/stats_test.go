@@ -0,0 +1,180 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/urizennnn/autostandup-reposcanner/config"
+	"github.com/urizennnn/autostandup-reposcanner/ratelimit"
+)
+
+// githubStub serves a repository of n commits on one branch: the REST
+// commit listing and GetCommit, and the GraphQL history query. It counts
+// the requests of each kind.
+type githubStub struct {
+	shas []string
+
+	list    atomic.Int64
+	rest    atomic.Int64
+	graphql atomic.Int64
+}
+
+func newGithubStub(n int) *githubStub {
+	s := &githubStub{}
+	for i := range n {
+		s.shas = append(s.shas, fmt.Sprintf("%040x", i+1))
+	}
+	return s
+}
+
+func (s *githubStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+		s.graphql.Add(1)
+		s.history(w, r)
+	case r.URL.Path == "/repos/o/r/commits":
+		s.list.Add(1)
+		s.listCommits(w, r)
+	case strings.HasPrefix(r.URL.Path, "/repos/o/r/commits/"):
+		s.rest.Add(1)
+		sha := strings.TrimPrefix(r.URL.Path, "/repos/o/r/commits/")
+		json.NewEncoder(w).Encode(map[string]any{
+			"sha": sha,
+			"files": []map[string]any{
+				{"filename": "main.go", "status": "modified", "additions": 3, "deletions": 1},
+				{"filename": "README.md", "status": "modified", "additions": 1, "deletions": 0},
+			},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *githubStub) listCommits(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 30
+	}
+	start := min((page-1)*perPage, len(s.shas))
+	end := min(start+perPage, len(s.shas))
+	if end < len(s.shas) {
+		next := *r.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+	out := make([]map[string]any, 0, end-start)
+	for _, sha := range s.shas[start:end] {
+		out = append(out, map[string]any{
+			"sha":    sha,
+			"commit": map[string]any{"message": "fix: thing", "author": map[string]any{"name": "A", "email": "a@example.com"}},
+		})
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+func (s *githubStub) history(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Variables struct {
+			After string `json:"after"`
+		} `json:"variables"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	start, _ := strconv.Atoi(body.Variables.After)
+	end := min(start+100, len(s.shas))
+
+	type node struct {
+		OID                     string `json:"oid"`
+		Additions               int    `json:"additions"`
+		Deletions               int    `json:"deletions"`
+		ChangedFilesIfAvailable int    `json:"changedFilesIfAvailable"`
+	}
+	nodes := make([]node, 0, end-start)
+	for _, sha := range s.shas[start:end] {
+		nodes = append(nodes, node{OID: sha, Additions: 4, Deletions: 1, ChangedFilesIfAvailable: 2})
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"repository": map[string]any{"object": map[string]any{
+		"history": map[string]any{
+			"nodes":    nodes,
+			"pageInfo": map[string]any{"hasNextPage": end < len(s.shas), "endCursor": strconv.Itoa(end)},
+		},
+	}}}})
+}
+
+func newStubClient(tb testing.TB, serverURL, source string) *Client {
+	cfg := &config.Config{
+		GithubConcurrency: 10,
+		CommitStatsSource: source,
+		GraphqlMinCommits: 30,
+		CacheSize:         10000,
+		EtagStore:         "off",
+		CommitCacheStore:  "memory",
+	}
+	shared, err := NewShared(cfg, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(serverURL + "/")
+	return &Client{
+		gh:      gh,
+		limiter: ratelimit.New(1_000_000, 1_000_000),
+		shared:  shared,
+		config:  cfg,
+	}
+}
+
+// BenchmarkCommitStats lists a 250-commit window and fetches its stats with
+// an empty cache, reporting the GitHub requests each source needs.
+func BenchmarkCommitStats(b *testing.B) {
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	const commits = 250
+	for _, source := range []string{StatsSourceREST, StatsSourceGraphQL} {
+		b.Run(source, func(b *testing.B) {
+			stub := newGithubStub(commits)
+			srv := httptest.NewServer(stub)
+			defer srv.Close()
+
+			ctx := context.Background()
+			req := ScanRequest{Since: time.Now().Add(-24 * time.Hour), Until: time.Now()}
+			for b.Loop() {
+				c := newStubClient(b, srv.URL, source)
+				listed, err := c.listBranchCommits(ctx, "o", "r", "main", req.Since, req.Until)
+				if err != nil {
+					b.Fatal(err)
+				}
+				got, err := c.buildCommits(ctx, "o", "r", listed, []string{"main"}, req, pathFilter{})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(got) != commits {
+					b.Fatalf("built %d commits, want %d", len(got), commits)
+				}
+			}
+			b.ReportMetric(float64(stub.list.Load())/float64(b.N), "list-requests/op")
+			b.ReportMetric(float64(stub.rest.Load())/float64(b.N), "rest-requests/op")
+			b.ReportMetric(float64(stub.graphql.Load())/float64(b.N), "graphql-requests/op")
+		})
+	}
+}
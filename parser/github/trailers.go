@@ -0,0 +1,89 @@
+package github
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+var trailerRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*)\s*:\s*(.+)$`)
+
+// trailerKeys are the keys that mark a paragraph as a trailer block. A final
+// paragraph of other "Key: value" lines, such as the "feat: add login" PR
+// title GitHub puts under "Merge pull request ...", stays in the message.
+var trailerKeys = map[string]bool{
+	"co-authored-by": true,
+	"signed-off-by":  true,
+	"reviewed-by":    true,
+	"acked-by":       true,
+	"tested-by":      true,
+	"reported-by":    true,
+	"suggested-by":   true,
+	"helped-by":      true,
+	"reviewed-on":    true,
+	"change-id":      true,
+	"cc":             true,
+}
+
+type trailers struct {
+	coAuthors   []ai.Person
+	signedOffBy []ai.Person
+	reviewedBy  []ai.Person
+}
+
+// parseTrailers splits the trailer block (the last paragraph, when every line
+// in it is a "Key: value" trailer and at least one key is in trailerKeys) off
+// a commit message and returns the remaining message with the people named in
+// the trailers we credit.
+func parseTrailers(msg string) (string, trailers) {
+	var t trailers
+	msg = strings.TrimRight(strings.ReplaceAll(msg, "\r\n", "\n"), "\n ")
+
+	cut := strings.LastIndex(msg, "\n\n")
+	if cut < 0 {
+		return msg, t
+	}
+
+	var found bool
+	for _, line := range strings.Split(msg[cut+2:], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m := trailerRe.FindStringSubmatch(line)
+		if m == nil {
+			return msg, trailers{}
+		}
+		key := strings.ToLower(m[1])
+		if trailerKeys[key] {
+			found = true
+		}
+		p, ok := parsePerson(m[2])
+		if !ok {
+			continue
+		}
+		switch key {
+		case "co-authored-by":
+			t.coAuthors = append(t.coAuthors, p)
+		case "signed-off-by":
+			t.signedOffBy = append(t.signedOffBy, p)
+		case "reviewed-by":
+			t.reviewedBy = append(t.reviewedBy, p)
+		}
+	}
+	if !found {
+		return msg, trailers{}
+	}
+	return strings.TrimRight(msg[:cut], "\n "), t
+}
+
+func parsePerson(v string) (ai.Person, bool) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(v))
+	if err != nil {
+		name := strings.TrimSpace(v)
+		return ai.Person{Name: name}, name != ""
+	}
+	return ai.Person{Name: addr.Name, Email: addr.Address}, true
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+func TestParseTrailers(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       string
+		want      string
+		coAuthors []ai.Person
+	}{
+		{
+			name: "no trailers",
+			msg:  "fix: handle empty window",
+			want: "fix: handle empty window",
+		},
+		{
+			name:      "co-author",
+			msg:       "feat: add login\n\nCo-authored-by: Jane Doe <jane@example.com>",
+			want:      "feat: add login",
+			coAuthors: []ai.Person{{Name: "Jane Doe", Email: "jane@example.com"}},
+		},
+		{
+			name: "merge commit keeps the pull request title",
+			msg:  "Merge pull request #3 from o/b\n\nfeat: add login",
+			want: "Merge pull request #3 from o/b\n\nfeat: add login",
+		},
+		{
+			name: "key-value body without a known key",
+			msg:  "chore: bump deps\n\nRefs: ABC-1\nfix: pin version",
+			want: "chore: bump deps\n\nRefs: ABC-1\nfix: pin version",
+		},
+		{
+			name: "known key among others",
+			msg:  "fix: race\n\nChange-Id: I123\nSigned-off-by: Bob <bob@example.com>",
+			want: "fix: race",
+		},
+		{
+			name: "prose paragraph",
+			msg:  "fix: race\n\nThis fixes it.\nCo-authored-by: Jane <jane@example.com>",
+			want: "fix: race\n\nThis fixes it.\nCo-authored-by: Jane <jane@example.com>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, tr := parseTrailers(tt.msg)
+			if got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(tr.coAuthors, tt.coAuthors) {
+				t.Errorf("co-authors = %+v, want %+v", tr.coAuthors, tt.coAuthors)
+			}
+		})
+	}
+}
@@ -1,21 +1,63 @@
 package github
 
 import (
+	"time"
+
 	"github.com/google/go-github/v74/github"
-	"github.com/urizennnn/autostandup-reposcanner/cache"
+	"github.com/urizennnn/autostandup-reposcanner/ai"
 	"github.com/urizennnn/autostandup-reposcanner/config"
 	"github.com/urizennnn/autostandup-reposcanner/ratelimit"
 )
 
 type Client struct {
-	gh      *github.Client
-	limiter *ratelimit.Limiter
-	cache   *cache.Cache
-	config  *config.Config
+	gh        *github.Client
+	limiter   *ratelimit.Limiter
+	shared    *Shared
+	config    *config.Config
+	summaries *ai.ResultCache
+	budget    *ai.Budget
+	identity  *identityResolver
+
+	installationID int64
+}
+
+type ScanRequest struct {
+	Owner        string
+	Repo         string
+	Branch       string
+	Branches     []string
+	AllBranches  bool
+	IncludePaths []string
+	ExcludePaths []string
+	IncludeDiffs bool
+	Format       string
+	Language     string
+	Mode         string
+	Contributor  string
+	Since        time.Time
+	Until        time.Time
+	ForceRefresh bool
 }
 
+// commitStats holds a commit's totals and, from REST, every file it changed
+// with patches. Only REST results are cached, with Files set and the totals
+// left to totals(); path filters are applied after the cache, so one entry
+// serves every filter.
 type commitStats struct {
-	Files     int
+	Files     []ai.FileChange
+	Changed   int
 	Additions int
 	Deletions int
+	// NoPatches marks an entry stored in the second tier, which keeps
+	// everything but the patches.
+	NoPatches bool `json:",omitempty"`
+}
+
+func (s commitStats) totals() commitStats {
+	out := commitStats{Files: s.Files, Changed: len(s.Files)}
+	for _, f := range s.Files {
+		out.Additions += f.Additions
+		out.Deletions += f.Deletions
+	}
+	return out
 }
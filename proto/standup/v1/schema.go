@@ -0,0 +1,14 @@
+package standupv1
+
+import _ "embed"
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative standup/v1/standup.proto
+
+// Proto is the source of standup.proto, published so consumers in other
+// languages can generate their own clients.
+//
+//go:embed standup.proto
+var Proto []byte
+
+// ProtoName is the file's import path, as consumers should lay it out.
+const ProtoName = "standup/v1/standup.proto"
@@ -0,0 +1,1536 @@
+// Standup results as published on scan:results with encoding "protobuf".
+//
+// Field names map to the JSON payload's keys (their lowerCamelCase JSON
+// names are identical), so a protobuf entry decodes to the same data as a
+// JSON one. Fields are only ever added; numbers are never reused. A
+// breaking change gets a new package version.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: standup/v1/standup.proto
+
+package standupv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StandupPayload is the "payload" field of a regular entry.
+type StandupPayload struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Repo            string                 `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Language        string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	Window          *Window                `protobuf:"bytes,4,opt,name=window,proto3" json:"window,omitempty"`
+	Technical       *TechnicalLevel        `protobuf:"bytes,5,opt,name=technical,proto3" json:"technical,omitempty"`
+	MildlyTechnical *SummaryLevel          `protobuf:"bytes,6,opt,name=mildly_technical,json=mildlyTechnical,proto3" json:"mildly_technical,omitempty"`
+	Layman          *SummaryLevel          `protobuf:"bytes,7,opt,name=layman,proto3" json:"layman,omitempty"`
+	Contributors    []*Contributor         `protobuf:"bytes,8,rep,name=contributors,proto3" json:"contributors,omitempty"`
+	Sections        []*ContributorSection  `protobuf:"bytes,9,rep,name=sections,proto3" json:"sections,omitempty"`
+	Releases        []*Release             `protobuf:"bytes,10,rep,name=releases,proto3" json:"releases,omitempty"`
+	Components      []*Component           `protobuf:"bytes,11,rep,name=components,proto3" json:"components,omitempty"`
+	Bots            []*BotActivity         `protobuf:"bytes,12,rep,name=bots,proto3" json:"bots,omitempty"`
+	// Content of custom formats, shaped by the format's schema.
+	Custom        *structpb.Struct `protobuf:"bytes,13,opt,name=custom,proto3" json:"custom,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StandupPayload) Reset() {
+	*x = StandupPayload{}
+	mi := &file_standup_v1_standup_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StandupPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StandupPayload) ProtoMessage() {}
+
+func (x *StandupPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StandupPayload.ProtoReflect.Descriptor instead.
+func (*StandupPayload) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StandupPayload) GetRepo() string {
+	if x != nil {
+		return x.Repo
+	}
+	return ""
+}
+
+func (x *StandupPayload) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *StandupPayload) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *StandupPayload) GetWindow() *Window {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetTechnical() *TechnicalLevel {
+	if x != nil {
+		return x.Technical
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetMildlyTechnical() *SummaryLevel {
+	if x != nil {
+		return x.MildlyTechnical
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetLayman() *SummaryLevel {
+	if x != nil {
+		return x.Layman
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetContributors() []*Contributor {
+	if x != nil {
+		return x.Contributors
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetSections() []*ContributorSection {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetReleases() []*Release {
+	if x != nil {
+		return x.Releases
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetComponents() []*Component {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetBots() []*BotActivity {
+	if x != nil {
+		return x.Bots
+	}
+	return nil
+}
+
+func (x *StandupPayload) GetCustom() *structpb.Struct {
+	if x != nil {
+		return x.Custom
+	}
+	return nil
+}
+
+// StandupResult is the "payload" field of a test standup entry.
+type StandupResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       *StandupPayload        `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Details       *UsageDetails          `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	IsTestStandup bool                   `protobuf:"varint,3,opt,name=is_test_standup,json=isTestStandup,proto3" json:"is_test_standup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StandupResult) Reset() {
+	*x = StandupResult{}
+	mi := &file_standup_v1_standup_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StandupResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StandupResult) ProtoMessage() {}
+
+func (x *StandupResult) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StandupResult.ProtoReflect.Descriptor instead.
+func (*StandupResult) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StandupResult) GetPayload() *StandupPayload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *StandupResult) GetDetails() *UsageDetails {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *StandupResult) GetIsTestStandup() bool {
+	if x != nil {
+		return x.IsTestStandup
+	}
+	return false
+}
+
+type UsageDetails struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Provider           string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Model              string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	PromptTokens       int64                  `protobuf:"varint,3,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CachedPromptTokens int64                  `protobuf:"varint,4,opt,name=cached_prompt_tokens,json=cachedPromptTokens,proto3" json:"cached_prompt_tokens,omitempty"`
+	CompletionTokens   int64                  `protobuf:"varint,5,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	TotalTokens        int64                  `protobuf:"varint,6,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	EstimatedCost      float64                `protobuf:"fixed64,7,opt,name=estimated_cost,json=estimatedCost,proto3" json:"estimated_cost,omitempty"`
+	SchemaVersion      string                 `protobuf:"bytes,8,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	CacheHit           bool                   `protobuf:"varint,9,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"`
+	RequestedModel     string                 `protobuf:"bytes,10,opt,name=requested_model,json=requestedModel,proto3" json:"requested_model,omitempty"`
+	BudgetDecision     string                 `protobuf:"bytes,11,opt,name=budget_decision,json=budgetDecision,proto3" json:"budget_decision,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UsageDetails) Reset() {
+	*x = UsageDetails{}
+	mi := &file_standup_v1_standup_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageDetails) ProtoMessage() {}
+
+func (x *UsageDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageDetails.ProtoReflect.Descriptor instead.
+func (*UsageDetails) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UsageDetails) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *UsageDetails) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *UsageDetails) GetPromptTokens() int64 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *UsageDetails) GetCachedPromptTokens() int64 {
+	if x != nil {
+		return x.CachedPromptTokens
+	}
+	return 0
+}
+
+func (x *UsageDetails) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+func (x *UsageDetails) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *UsageDetails) GetEstimatedCost() float64 {
+	if x != nil {
+		return x.EstimatedCost
+	}
+	return 0
+}
+
+func (x *UsageDetails) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *UsageDetails) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
+func (x *UsageDetails) GetRequestedModel() string {
+	if x != nil {
+		return x.RequestedModel
+	}
+	return ""
+}
+
+func (x *UsageDetails) GetBudgetDecision() string {
+	if x != nil {
+		return x.BudgetDecision
+	}
+	return ""
+}
+
+type Window struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         string                 `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	Until         string                 `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Window) Reset() {
+	*x = Window{}
+	mi := &file_standup_v1_standup_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Window) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Window) ProtoMessage() {}
+
+func (x *Window) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Window.ProtoReflect.Descriptor instead.
+func (*Window) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Window) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *Window) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+type TechnicalLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        string                 `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	WhatWorkedOn  []string               `protobuf:"bytes,2,rep,name=what_worked_on,json=whatWorkedOn,proto3" json:"what_worked_on,omitempty"`
+	FilesChanged  *FilesChanged          `protobuf:"bytes,3,opt,name=files_changed,json=filesChanged,proto3" json:"files_changed,omitempty"`
+	Commits       []string               `protobuf:"bytes,4,rep,name=commits,proto3" json:"commits,omitempty"`
+	Ci            *CIStatus              `protobuf:"bytes,5,opt,name=ci,proto3" json:"ci,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TechnicalLevel) Reset() {
+	*x = TechnicalLevel{}
+	mi := &file_standup_v1_standup_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TechnicalLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TechnicalLevel) ProtoMessage() {}
+
+func (x *TechnicalLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TechnicalLevel.ProtoReflect.Descriptor instead.
+func (*TechnicalLevel) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TechnicalLevel) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *TechnicalLevel) GetWhatWorkedOn() []string {
+	if x != nil {
+		return x.WhatWorkedOn
+	}
+	return nil
+}
+
+func (x *TechnicalLevel) GetFilesChanged() *FilesChanged {
+	if x != nil {
+		return x.FilesChanged
+	}
+	return nil
+}
+
+func (x *TechnicalLevel) GetCommits() []string {
+	if x != nil {
+		return x.Commits
+	}
+	return nil
+}
+
+func (x *TechnicalLevel) GetCi() *CIStatus {
+	if x != nil {
+		return x.Ci
+	}
+	return nil
+}
+
+type SummaryLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        string                 `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	WhatWorkedOn  []string               `protobuf:"bytes,2,rep,name=what_worked_on,json=whatWorkedOn,proto3" json:"what_worked_on,omitempty"`
+	Impact        string                 `protobuf:"bytes,3,opt,name=impact,proto3" json:"impact,omitempty"`
+	Focus         string                 `protobuf:"bytes,4,opt,name=focus,proto3" json:"focus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SummaryLevel) Reset() {
+	*x = SummaryLevel{}
+	mi := &file_standup_v1_standup_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SummaryLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryLevel) ProtoMessage() {}
+
+func (x *SummaryLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryLevel.ProtoReflect.Descriptor instead.
+func (*SummaryLevel) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SummaryLevel) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *SummaryLevel) GetWhatWorkedOn() []string {
+	if x != nil {
+		return x.WhatWorkedOn
+	}
+	return nil
+}
+
+func (x *SummaryLevel) GetImpact() string {
+	if x != nil {
+		return x.Impact
+	}
+	return ""
+}
+
+func (x *SummaryLevel) GetFocus() string {
+	if x != nil {
+		return x.Focus
+	}
+	return ""
+}
+
+type FilesChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         int32                  `protobuf:"varint,1,opt,name=files,proto3" json:"files,omitempty"`
+	Additions     int32                  `protobuf:"varint,2,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions     int32                  `protobuf:"varint,3,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	Languages     []*ChangeShare         `protobuf:"bytes,4,rep,name=languages,proto3" json:"languages,omitempty"`
+	Categories    []*ChangeShare         `protobuf:"bytes,5,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilesChanged) Reset() {
+	*x = FilesChanged{}
+	mi := &file_standup_v1_standup_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilesChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilesChanged) ProtoMessage() {}
+
+func (x *FilesChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesChanged.ProtoReflect.Descriptor instead.
+func (*FilesChanged) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilesChanged) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *FilesChanged) GetAdditions() int32 {
+	if x != nil {
+		return x.Additions
+	}
+	return 0
+}
+
+func (x *FilesChanged) GetDeletions() int32 {
+	if x != nil {
+		return x.Deletions
+	}
+	return 0
+}
+
+func (x *FilesChanged) GetLanguages() []*ChangeShare {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *FilesChanged) GetCategories() []*ChangeShare {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ChangeShare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Files         int32                  `protobuf:"varint,2,opt,name=files,proto3" json:"files,omitempty"`
+	Additions     int32                  `protobuf:"varint,3,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions     int32                  `protobuf:"varint,4,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeShare) Reset() {
+	*x = ChangeShare{}
+	mi := &file_standup_v1_standup_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeShare) ProtoMessage() {}
+
+func (x *ChangeShare) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeShare.ProtoReflect.Descriptor instead.
+func (*ChangeShare) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ChangeShare) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChangeShare) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *ChangeShare) GetAdditions() int32 {
+	if x != nil {
+		return x.Additions
+	}
+	return 0
+}
+
+func (x *ChangeShare) GetDeletions() int32 {
+	if x != nil {
+		return x.Deletions
+	}
+	return 0
+}
+
+type CIStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sha   string                 `protobuf:"bytes,1,opt,name=sha,proto3" json:"sha,omitempty"`
+	Ref   string                 `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	// success, failure or pending.
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	RedSince      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=red_since,json=redSince,proto3" json:"red_since,omitempty"`
+	Failing       []*FailingCheck        `protobuf:"bytes,5,rep,name=failing,proto3" json:"failing,omitempty"`
+	Merges        []*CIStatus            `protobuf:"bytes,6,rep,name=merges,proto3" json:"merges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CIStatus) Reset() {
+	*x = CIStatus{}
+	mi := &file_standup_v1_standup_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CIStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CIStatus) ProtoMessage() {}
+
+func (x *CIStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CIStatus.ProtoReflect.Descriptor instead.
+func (*CIStatus) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CIStatus) GetSha() string {
+	if x != nil {
+		return x.Sha
+	}
+	return ""
+}
+
+func (x *CIStatus) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *CIStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CIStatus) GetRedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RedSince
+	}
+	return nil
+}
+
+func (x *CIStatus) GetFailing() []*FailingCheck {
+	if x != nil {
+		return x.Failing
+	}
+	return nil
+}
+
+func (x *CIStatus) GetMerges() []*CIStatus {
+	if x != nil {
+		return x.Merges
+	}
+	return nil
+}
+
+type FailingCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Conclusion    string                 `protobuf:"bytes,2,opt,name=conclusion,proto3" json:"conclusion,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailingCheck) Reset() {
+	*x = FailingCheck{}
+	mi := &file_standup_v1_standup_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailingCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailingCheck) ProtoMessage() {}
+
+func (x *FailingCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailingCheck.ProtoReflect.Descriptor instead.
+func (*FailingCheck) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FailingCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FailingCheck) GetConclusion() string {
+	if x != nil {
+		return x.Conclusion
+	}
+	return ""
+}
+
+func (x *FailingCheck) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *FailingCheck) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type Contributor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Login         string                 `protobuf:"bytes,3,opt,name=login,proto3" json:"login,omitempty"`
+	Commits       int32                  `protobuf:"varint,4,opt,name=commits,proto3" json:"commits,omitempty"`
+	CoAuthored    int32                  `protobuf:"varint,5,opt,name=co_authored,json=coAuthored,proto3" json:"co_authored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contributor) Reset() {
+	*x = Contributor{}
+	mi := &file_standup_v1_standup_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contributor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contributor) ProtoMessage() {}
+
+func (x *Contributor) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contributor.ProtoReflect.Descriptor instead.
+func (*Contributor) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Contributor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Contributor) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Contributor) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *Contributor) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *Contributor) GetCoAuthored() int32 {
+	if x != nil {
+		return x.CoAuthored
+	}
+	return 0
+}
+
+type ContributorSection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        string                 `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	CommitCount   int32                  `protobuf:"varint,4,opt,name=commit_count,json=commitCount,proto3" json:"commit_count,omitempty"`
+	CoAuthored    int32                  `protobuf:"varint,5,opt,name=co_authored,json=coAuthored,proto3" json:"co_authored,omitempty"`
+	FilesChanged  *FilesChanged          `protobuf:"bytes,6,opt,name=files_changed,json=filesChanged,proto3" json:"files_changed,omitempty"`
+	WhatWorkedOn  []string               `protobuf:"bytes,7,rep,name=what_worked_on,json=whatWorkedOn,proto3" json:"what_worked_on,omitempty"`
+	Commits       []string               `protobuf:"bytes,8,rep,name=commits,proto3" json:"commits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContributorSection) Reset() {
+	*x = ContributorSection{}
+	mi := &file_standup_v1_standup_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContributorSection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContributorSection) ProtoMessage() {}
+
+func (x *ContributorSection) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContributorSection.ProtoReflect.Descriptor instead.
+func (*ContributorSection) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ContributorSection) GetHandle() string {
+	if x != nil {
+		return x.Handle
+	}
+	return ""
+}
+
+func (x *ContributorSection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContributorSection) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ContributorSection) GetCommitCount() int32 {
+	if x != nil {
+		return x.CommitCount
+	}
+	return 0
+}
+
+func (x *ContributorSection) GetCoAuthored() int32 {
+	if x != nil {
+		return x.CoAuthored
+	}
+	return 0
+}
+
+func (x *ContributorSection) GetFilesChanged() *FilesChanged {
+	if x != nil {
+		return x.FilesChanged
+	}
+	return nil
+}
+
+func (x *ContributorSection) GetWhatWorkedOn() []string {
+	if x != nil {
+		return x.WhatWorkedOn
+	}
+	return nil
+}
+
+func (x *ContributorSection) GetCommits() []string {
+	if x != nil {
+		return x.Commits
+	}
+	return nil
+}
+
+type Release struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Notes         string                 `protobuf:"bytes,3,opt,name=notes,proto3" json:"notes,omitempty"`
+	Author        string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Prerelease    bool                   `protobuf:"varint,5,opt,name=prerelease,proto3" json:"prerelease,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=at,proto3" json:"at,omitempty"`
+	Url           string                 `protobuf:"bytes,7,opt,name=url,proto3" json:"url,omitempty"`
+	Range         *ReleaseRange          `protobuf:"bytes,8,opt,name=range,proto3" json:"range,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Release) Reset() {
+	*x = Release{}
+	mi := &file_standup_v1_standup_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Release) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Release) ProtoMessage() {}
+
+func (x *Release) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Release.ProtoReflect.Descriptor instead.
+func (*Release) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Release) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *Release) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Release) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Release) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Release) GetPrerelease() bool {
+	if x != nil {
+		return x.Prerelease
+	}
+	return false
+}
+
+func (x *Release) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *Release) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Release) GetRange() *ReleaseRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+type ReleaseRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Commits       int32                  `protobuf:"varint,3,opt,name=commits,proto3" json:"commits,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseRange) Reset() {
+	*x = ReleaseRange{}
+	mi := &file_standup_v1_standup_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRange) ProtoMessage() {}
+
+func (x *ReleaseRange) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRange.ProtoReflect.Descriptor instead.
+func (*ReleaseRange) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReleaseRange) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ReleaseRange) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ReleaseRange) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *ReleaseRange) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Component struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// team or directory.
+	Kind          string        `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Commits       int32         `protobuf:"varint,3,opt,name=commits,proto3" json:"commits,omitempty"`
+	FilesChanged  *FilesChanged `protobuf:"bytes,4,opt,name=files_changed,json=filesChanged,proto3" json:"files_changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Component) Reset() {
+	*x = Component{}
+	mi := &file_standup_v1_standup_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Component) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Component) ProtoMessage() {}
+
+func (x *Component) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Component.ProtoReflect.Descriptor instead.
+func (*Component) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Component) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Component) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Component) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *Component) GetFilesChanged() *FilesChanged {
+	if x != nil {
+		return x.FilesChanged
+	}
+	return nil
+}
+
+type BotActivity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Commits       int32                  `protobuf:"varint,2,opt,name=commits,proto3" json:"commits,omitempty"`
+	Changes       []string               `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BotActivity) Reset() {
+	*x = BotActivity{}
+	mi := &file_standup_v1_standup_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BotActivity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BotActivity) ProtoMessage() {}
+
+func (x *BotActivity) ProtoReflect() protoreflect.Message {
+	mi := &file_standup_v1_standup_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BotActivity.ProtoReflect.Descriptor instead.
+func (*BotActivity) Descriptor() ([]byte, []int) {
+	return file_standup_v1_standup_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BotActivity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BotActivity) GetCommits() int32 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *BotActivity) GetChanges() []string {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+var File_standup_v1_standup_proto protoreflect.FileDescriptor
+
+const file_standup_v1_standup_proto_rawDesc = "" +
+	"\n" +
+	"\x18standup/v1/standup.proto\x12\x16autostandup.standup.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xde\x05\n" +
+	"\x0eStandupPayload\x12\x12\n" +
+	"\x04repo\x18\x01 \x01(\tR\x04repo\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x126\n" +
+	"\x06window\x18\x04 \x01(\v2\x1e.autostandup.standup.v1.WindowR\x06window\x12D\n" +
+	"\ttechnical\x18\x05 \x01(\v2&.autostandup.standup.v1.TechnicalLevelR\ttechnical\x12O\n" +
+	"\x10mildly_technical\x18\x06 \x01(\v2$.autostandup.standup.v1.SummaryLevelR\x0fmildlyTechnical\x12<\n" +
+	"\x06layman\x18\a \x01(\v2$.autostandup.standup.v1.SummaryLevelR\x06layman\x12G\n" +
+	"\fcontributors\x18\b \x03(\v2#.autostandup.standup.v1.ContributorR\fcontributors\x12F\n" +
+	"\bsections\x18\t \x03(\v2*.autostandup.standup.v1.ContributorSectionR\bsections\x12;\n" +
+	"\breleases\x18\n" +
+	" \x03(\v2\x1f.autostandup.standup.v1.ReleaseR\breleases\x12A\n" +
+	"\n" +
+	"components\x18\v \x03(\v2!.autostandup.standup.v1.ComponentR\n" +
+	"components\x127\n" +
+	"\x04bots\x18\f \x03(\v2#.autostandup.standup.v1.BotActivityR\x04bots\x12/\n" +
+	"\x06custom\x18\r \x01(\v2\x17.google.protobuf.StructR\x06custom\"\xb9\x01\n" +
+	"\rStandupResult\x12@\n" +
+	"\apayload\x18\x01 \x01(\v2&.autostandup.standup.v1.StandupPayloadR\apayload\x12>\n" +
+	"\adetails\x18\x02 \x01(\v2$.autostandup.standup.v1.UsageDetailsR\adetails\x12&\n" +
+	"\x0fis_test_standup\x18\x03 \x01(\bR\risTestStandup\"\xa4\x03\n" +
+	"\fUsageDetails\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12#\n" +
+	"\rprompt_tokens\x18\x03 \x01(\x03R\fpromptTokens\x120\n" +
+	"\x14cached_prompt_tokens\x18\x04 \x01(\x03R\x12cachedPromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x05 \x01(\x03R\x10completionTokens\x12!\n" +
+	"\ftotal_tokens\x18\x06 \x01(\x03R\vtotalTokens\x12%\n" +
+	"\x0eestimated_cost\x18\a \x01(\x01R\restimatedCost\x12%\n" +
+	"\x0eschema_version\x18\b \x01(\tR\rschemaVersion\x12\x1b\n" +
+	"\tcache_hit\x18\t \x01(\bR\bcacheHit\x12'\n" +
+	"\x0frequested_model\x18\n" +
+	" \x01(\tR\x0erequestedModel\x12'\n" +
+	"\x0fbudget_decision\x18\v \x01(\tR\x0ebudgetDecision\"4\n" +
+	"\x06Window\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x02 \x01(\tR\x05until\"\xe5\x01\n" +
+	"\x0eTechnicalLevel\x12\x16\n" +
+	"\x06header\x18\x01 \x01(\tR\x06header\x12$\n" +
+	"\x0ewhat_worked_on\x18\x02 \x03(\tR\fwhatWorkedOn\x12I\n" +
+	"\rfiles_changed\x18\x03 \x01(\v2$.autostandup.standup.v1.FilesChangedR\ffilesChanged\x12\x18\n" +
+	"\acommits\x18\x04 \x03(\tR\acommits\x120\n" +
+	"\x02ci\x18\x05 \x01(\v2 .autostandup.standup.v1.CIStatusR\x02ci\"z\n" +
+	"\fSummaryLevel\x12\x16\n" +
+	"\x06header\x18\x01 \x01(\tR\x06header\x12$\n" +
+	"\x0ewhat_worked_on\x18\x02 \x03(\tR\fwhatWorkedOn\x12\x16\n" +
+	"\x06impact\x18\x03 \x01(\tR\x06impact\x12\x14\n" +
+	"\x05focus\x18\x04 \x01(\tR\x05focus\"\xe8\x01\n" +
+	"\fFilesChanged\x12\x14\n" +
+	"\x05files\x18\x01 \x01(\x05R\x05files\x12\x1c\n" +
+	"\tadditions\x18\x02 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\x03 \x01(\x05R\tdeletions\x12A\n" +
+	"\tlanguages\x18\x04 \x03(\v2#.autostandup.standup.v1.ChangeShareR\tlanguages\x12C\n" +
+	"\n" +
+	"categories\x18\x05 \x03(\v2#.autostandup.standup.v1.ChangeShareR\n" +
+	"categories\"s\n" +
+	"\vChangeShare\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05files\x18\x02 \x01(\x05R\x05files\x12\x1c\n" +
+	"\tadditions\x18\x03 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\x04 \x01(\x05R\tdeletions\"\xf7\x01\n" +
+	"\bCIStatus\x12\x10\n" +
+	"\x03sha\x18\x01 \x01(\tR\x03sha\x12\x10\n" +
+	"\x03ref\x18\x02 \x01(\tR\x03ref\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x127\n" +
+	"\tred_since\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bredSince\x12>\n" +
+	"\afailing\x18\x05 \x03(\v2$.autostandup.standup.v1.FailingCheckR\afailing\x128\n" +
+	"\x06merges\x18\x06 \x03(\v2 .autostandup.standup.v1.CIStatusR\x06merges\"\x93\x01\n" +
+	"\fFailingCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"conclusion\x18\x02 \x01(\tR\n" +
+	"conclusion\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12=\n" +
+	"\fcompleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x88\x01\n" +
+	"\vContributor\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05login\x18\x03 \x01(\tR\x05login\x12\x18\n" +
+	"\acommits\x18\x04 \x01(\x05R\acommits\x12\x1f\n" +
+	"\vco_authored\x18\x05 \x01(\x05R\n" +
+	"coAuthored\"\xa5\x02\n" +
+	"\x12ContributorSection\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\tR\x06handle\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12!\n" +
+	"\fcommit_count\x18\x04 \x01(\x05R\vcommitCount\x12\x1f\n" +
+	"\vco_authored\x18\x05 \x01(\x05R\n" +
+	"coAuthored\x12I\n" +
+	"\rfiles_changed\x18\x06 \x01(\v2$.autostandup.standup.v1.FilesChangedR\ffilesChanged\x12$\n" +
+	"\x0ewhat_worked_on\x18\a \x03(\tR\fwhatWorkedOn\x12\x18\n" +
+	"\acommits\x18\b \x03(\tR\acommits\"\xf7\x01\n" +
+	"\aRelease\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05notes\x18\x03 \x01(\tR\x05notes\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x12\x1e\n" +
+	"\n" +
+	"prerelease\x18\x05 \x01(\bR\n" +
+	"prerelease\x12*\n" +
+	"\x02at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x10\n" +
+	"\x03url\x18\a \x01(\tR\x03url\x12:\n" +
+	"\x05range\x18\b \x01(\v2$.autostandup.standup.v1.ReleaseRangeR\x05range\"^\n" +
+	"\fReleaseRange\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x18\n" +
+	"\acommits\x18\x03 \x01(\x05R\acommits\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\x98\x01\n" +
+	"\tComponent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x18\n" +
+	"\acommits\x18\x03 \x01(\x05R\acommits\x12I\n" +
+	"\rfiles_changed\x18\x04 \x01(\v2$.autostandup.standup.v1.FilesChangedR\ffilesChanged\"U\n" +
+	"\vBotActivity\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acommits\x18\x02 \x01(\x05R\acommits\x12\x18\n" +
+	"\achanges\x18\x03 \x03(\tR\achangesBIZGgithub.com/urizennnn/autostandup-reposcanner/proto/standup/v1;standupv1b\x06proto3"
+
+var (
+	file_standup_v1_standup_proto_rawDescOnce sync.Once
+	file_standup_v1_standup_proto_rawDescData []byte
+)
+
+func file_standup_v1_standup_proto_rawDescGZIP() []byte {
+	file_standup_v1_standup_proto_rawDescOnce.Do(func() {
+		file_standup_v1_standup_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_standup_v1_standup_proto_rawDesc), len(file_standup_v1_standup_proto_rawDesc)))
+	})
+	return file_standup_v1_standup_proto_rawDescData
+}
+
+var file_standup_v1_standup_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_standup_v1_standup_proto_goTypes = []any{
+	(*StandupPayload)(nil),        // 0: autostandup.standup.v1.StandupPayload
+	(*StandupResult)(nil),         // 1: autostandup.standup.v1.StandupResult
+	(*UsageDetails)(nil),          // 2: autostandup.standup.v1.UsageDetails
+	(*Window)(nil),                // 3: autostandup.standup.v1.Window
+	(*TechnicalLevel)(nil),        // 4: autostandup.standup.v1.TechnicalLevel
+	(*SummaryLevel)(nil),          // 5: autostandup.standup.v1.SummaryLevel
+	(*FilesChanged)(nil),          // 6: autostandup.standup.v1.FilesChanged
+	(*ChangeShare)(nil),           // 7: autostandup.standup.v1.ChangeShare
+	(*CIStatus)(nil),              // 8: autostandup.standup.v1.CIStatus
+	(*FailingCheck)(nil),          // 9: autostandup.standup.v1.FailingCheck
+	(*Contributor)(nil),           // 10: autostandup.standup.v1.Contributor
+	(*ContributorSection)(nil),    // 11: autostandup.standup.v1.ContributorSection
+	(*Release)(nil),               // 12: autostandup.standup.v1.Release
+	(*ReleaseRange)(nil),          // 13: autostandup.standup.v1.ReleaseRange
+	(*Component)(nil),             // 14: autostandup.standup.v1.Component
+	(*BotActivity)(nil),           // 15: autostandup.standup.v1.BotActivity
+	(*structpb.Struct)(nil),       // 16: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_standup_v1_standup_proto_depIdxs = []int32{
+	3,  // 0: autostandup.standup.v1.StandupPayload.window:type_name -> autostandup.standup.v1.Window
+	4,  // 1: autostandup.standup.v1.StandupPayload.technical:type_name -> autostandup.standup.v1.TechnicalLevel
+	5,  // 2: autostandup.standup.v1.StandupPayload.mildly_technical:type_name -> autostandup.standup.v1.SummaryLevel
+	5,  // 3: autostandup.standup.v1.StandupPayload.layman:type_name -> autostandup.standup.v1.SummaryLevel
+	10, // 4: autostandup.standup.v1.StandupPayload.contributors:type_name -> autostandup.standup.v1.Contributor
+	11, // 5: autostandup.standup.v1.StandupPayload.sections:type_name -> autostandup.standup.v1.ContributorSection
+	12, // 6: autostandup.standup.v1.StandupPayload.releases:type_name -> autostandup.standup.v1.Release
+	14, // 7: autostandup.standup.v1.StandupPayload.components:type_name -> autostandup.standup.v1.Component
+	15, // 8: autostandup.standup.v1.StandupPayload.bots:type_name -> autostandup.standup.v1.BotActivity
+	16, // 9: autostandup.standup.v1.StandupPayload.custom:type_name -> google.protobuf.Struct
+	0,  // 10: autostandup.standup.v1.StandupResult.payload:type_name -> autostandup.standup.v1.StandupPayload
+	2,  // 11: autostandup.standup.v1.StandupResult.details:type_name -> autostandup.standup.v1.UsageDetails
+	6,  // 12: autostandup.standup.v1.TechnicalLevel.files_changed:type_name -> autostandup.standup.v1.FilesChanged
+	8,  // 13: autostandup.standup.v1.TechnicalLevel.ci:type_name -> autostandup.standup.v1.CIStatus
+	7,  // 14: autostandup.standup.v1.FilesChanged.languages:type_name -> autostandup.standup.v1.ChangeShare
+	7,  // 15: autostandup.standup.v1.FilesChanged.categories:type_name -> autostandup.standup.v1.ChangeShare
+	17, // 16: autostandup.standup.v1.CIStatus.red_since:type_name -> google.protobuf.Timestamp
+	9,  // 17: autostandup.standup.v1.CIStatus.failing:type_name -> autostandup.standup.v1.FailingCheck
+	8,  // 18: autostandup.standup.v1.CIStatus.merges:type_name -> autostandup.standup.v1.CIStatus
+	17, // 19: autostandup.standup.v1.FailingCheck.completed_at:type_name -> google.protobuf.Timestamp
+	6,  // 20: autostandup.standup.v1.ContributorSection.files_changed:type_name -> autostandup.standup.v1.FilesChanged
+	17, // 21: autostandup.standup.v1.Release.at:type_name -> google.protobuf.Timestamp
+	13, // 22: autostandup.standup.v1.Release.range:type_name -> autostandup.standup.v1.ReleaseRange
+	6,  // 23: autostandup.standup.v1.Component.files_changed:type_name -> autostandup.standup.v1.FilesChanged
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_standup_v1_standup_proto_init() }
+func file_standup_v1_standup_proto_init() {
+	if File_standup_v1_standup_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_standup_v1_standup_proto_rawDesc), len(file_standup_v1_standup_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_standup_v1_standup_proto_goTypes,
+		DependencyIndexes: file_standup_v1_standup_proto_depIdxs,
+		MessageInfos:      file_standup_v1_standup_proto_msgTypes,
+	}.Build()
+	File_standup_v1_standup_proto = out.File
+	file_standup_v1_standup_proto_goTypes = nil
+	file_standup_v1_standup_proto_depIdxs = nil
+}
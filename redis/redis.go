@@ -10,8 +10,12 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/urizennnn/autostandup-reposcanner/ai"
+	"github.com/urizennnn/autostandup-reposcanner/codec"
 	"github.com/urizennnn/autostandup-reposcanner/config"
+	"github.com/urizennnn/autostandup-reposcanner/delivery"
 	"github.com/urizennnn/autostandup-reposcanner/parser/github"
+	standupv1 "github.com/urizennnn/autostandup-reposcanner/proto/standup/v1"
+	"github.com/urizennnn/autostandup-reposcanner/render"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -39,7 +43,17 @@ func ConnectToRedisURL(url string, connTimeout time.Duration) (*redis.Client, er
 	return rdb, nil
 }
 
-func WatchStreams(ctx context.Context, rdb *redis.Client, stream, group, consumer string, cfg *config.Config) error {
+// PublishSchema stores the protobuf schema of scan:results payloads under
+// schema:<path>, so consumers can fetch the one this scanner encodes with.
+func PublishSchema(ctx context.Context, rdb *redis.Client) error {
+	key := "schema:" + standupv1.ProtoName
+	if err := rdb.Set(ctx, key, standupv1.Proto, 0).Err(); err != nil {
+		return fmt.Errorf("set %s: %w", key, err)
+	}
+	return nil
+}
+
+func WatchStreams(ctx context.Context, rdb *redis.Client, stream, group, consumer string, cfg *config.Config, shared *github.Shared, deliveries *delivery.Dispatcher) error {
 	log.Printf("[INFO] watching stream=%s group=%s consumer=%s workers=%d", stream, group, consumer, cfg.WorkerCount)
 
 	jobs := make(chan redis.XMessage, cfg.WorkerCount*2)
@@ -49,7 +63,7 @@ func WatchStreams(ctx context.Context, rdb *redis.Client, stream, group, consume
 		workerID := i
 		g.Go(func() error {
 			for msg := range jobs {
-				if err := processMessage(ctx, msg, rdb, stream, group, cfg); err != nil {
+				if err := processMessage(ctx, msg, rdb, stream, group, cfg, shared, deliveries); err != nil {
 					log.Printf("[ERROR] worker %d processing %s: %v", workerID, msg.ID, err)
 				}
 			}
@@ -107,7 +121,7 @@ func WatchStreams(ctx context.Context, rdb *redis.Client, stream, group, consume
 	return g.Wait()
 }
 
-func processMessage(ctx context.Context, msg redis.XMessage, rdb *redis.Client, stream, group string, cfg *config.Config) error {
+func processMessage(ctx context.Context, msg redis.XMessage, rdb *redis.Client, stream, group string, cfg *config.Config, shared *github.Shared, deliveries *delivery.Dispatcher) error {
 	ctx, cancel := context.WithTimeout(ctx, cfg.MessageTimeout)
 	defer cancel()
 
@@ -119,7 +133,7 @@ func processMessage(ctx context.Context, msg redis.XMessage, rdb *redis.Client,
 
 	maxRetries := cfg.MaxRetries
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := handleAndParseMessageEvent(ctx, msg, rdb, cfg)
+		err := handleAndParseMessageEvent(ctx, msg, rdb, cfg, shared, deliveries)
 		if err == nil {
 			return nil
 		}
@@ -148,7 +162,7 @@ func isTransient(err error) bool {
 		strings.Contains(errStr, "temporary")
 }
 
-func handleAndParseMessageEvent(ctx context.Context, msg redis.XMessage, rdb *redis.Client, cfg *config.Config) error {
+func handleAndParseMessageEvent(ctx context.Context, msg redis.XMessage, rdb *redis.Client, cfg *config.Config, shared *github.Shared, deliveries *delivery.Dispatcher) error {
 	log.Printf("[INFO] processing message %s", msg.ID)
 
 	payload, err := extractAndValidatePayload(msg)
@@ -161,12 +175,32 @@ func handleAndParseMessageEvent(ctx context.Context, msg redis.XMessage, rdb *re
 		return err
 	}
 
-	result, err := processRepoScan(ctx, payload, cfg)
+	result, err := processRepoScan(ctx, rdb, payload, cfg, shared)
 	if err != nil {
 		return err
 	}
 
-	return publishResult(ctx, rdb, result, payload, cfg)
+	id, err := publishResult(ctx, rdb, result, payload, cfg)
+	if err != nil {
+		return err
+	}
+	// A window without activity publishes an empty payload; there is
+	// nothing worth posting or mailing.
+	if payload.Delivery != nil && result.Payload.Repo == "" {
+		log.Printf("[INFO] no activity, skipping delivery id=%s repo=%s/%s", id, payload.Owner, payload.Repo)
+	} else if payload.Delivery != nil {
+		deliveries.Deliver(payload.Delivery, delivery.Message{
+			ID:            id,
+			Repo:          result.Payload.Repo,
+			From:          payload.From.UTC().Format(time.RFC3339),
+			To:            payload.To.UTC().Format(time.RFC3339),
+			Format:        payload.Format,
+			Language:      result.Payload.Language,
+			IsTestStandup: payload.IsTestStandup,
+			Payload:       result.Payload,
+		})
+	}
+	return nil
 }
 
 func extractAndValidatePayload(msg redis.XMessage) (QueueMessage, error) {
@@ -174,10 +208,33 @@ func extractAndValidatePayload(msg redis.XMessage) (QueueMessage, error) {
 	if err != nil {
 		return QueueMessage{}, fmt.Errorf("extracting queue payload: %w", err)
 	}
+	if _, err := ai.ResolveFormat(payload.Format); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	if payload.Language, err = ai.ResolveLanguage(payload.Language); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	if payload.Mode, err = ai.ResolveMode(payload.Mode); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	if err := github.ValidatePathFilter(payload.IncludePaths, payload.ExcludePaths); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	if _, err := render.ParseKinds(payload.Renderings); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	if err := delivery.ValidateTargets(payload.Delivery); err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	enc, err := codec.Resolve(payload.Encoding)
+	if err != nil {
+		return payload, fmt.Errorf("invalid queue payload: %w", err)
+	}
+	payload.Encoding = string(enc)
 	return payload, nil
 }
 
-func processRepoScan(ctx context.Context, payload QueueMessage, cfg *config.Config) (ai.SummarizeResult, error) {
+func processRepoScan(ctx context.Context, rdb *redis.Client, payload QueueMessage, cfg *config.Config, shared *github.Shared) (ai.SummarizeResult, error) {
 	githubPrivateKey, err := config.FetchSecretByName("APP_GITHUB_PRIVATE_KEY")
 	if err != nil {
 		return ai.SummarizeResult{}, fmt.Errorf("fetching github private key: %w", err)
@@ -188,74 +245,113 @@ func processRepoScan(ctx context.Context, payload QueueMessage, cfg *config.Conf
 		return ai.SummarizeResult{}, fmt.Errorf("fetching github client id: %w", err)
 	}
 
-	client, err := github.NewClient(cfg, []byte(githubPrivateKey), githubClientID, payload.InstallationID)
+	client, err := github.NewClient(cfg, rdb, shared, []byte(githubPrivateKey), githubClientID, payload.InstallationID)
 	if err != nil {
 		return ai.SummarizeResult{}, fmt.Errorf("creating github client: %w", err)
 	}
 
-	return client.ListCommits(ctx, payload.Owner, payload.Repo, payload.Branch, payload.Format, payload.From, payload.To)
+	return client.ListCommits(ctx, github.ScanRequest{
+		Owner:        payload.Owner,
+		Repo:         payload.Repo,
+		Branch:       payload.Branch,
+		Branches:     payload.Branches,
+		AllBranches:  payload.AllBranches,
+		IncludePaths: payload.IncludePaths,
+		ExcludePaths: payload.ExcludePaths,
+		IncludeDiffs: payload.IncludeDiffs,
+		Format:       payload.Format,
+		Language:     payload.Language,
+		Mode:         payload.Mode,
+		Contributor:  payload.Contributor,
+		Since:        payload.From,
+		Until:        payload.To,
+		ForceRefresh: payload.ForceRefresh,
+	})
 }
 
-func publishResult(ctx context.Context, rdb *redis.Client, result ai.SummarizeResult, payload QueueMessage, cfg *config.Config) error {
+func publishResult(ctx context.Context, rdb *redis.Client, result ai.SummarizeResult, payload QueueMessage, cfg *config.Config) (string, error) {
 	isTestStandupFlag := ctx.Value(contextKey("isTestStandup")).(bool)
+	enc, _ := codec.Resolve(payload.Encoding)
 
 	if isTestStandupFlag {
-		testPayload := map[string]any{
-			"payload":       result.Payload,
-			"details":       result.Details,
-			"isTestStandup": true,
-		}
-		testPayloadBytes, err := json.Marshal(testPayload)
+		testPayloadBytes, err := codec.EncodeTestResult(enc, codec.TestResult{
+			Payload:       result.Payload,
+			Details:       result.Details,
+			IsTestStandup: true,
+		})
 		if err != nil {
-			return fmt.Errorf("marshal test payload: %w", err)
+			return "", fmt.Errorf("encode test payload as %s: %w", enc, err)
+		}
+		values := map[string]any{
+			"payload":       string(testPayloadBytes),
+			"encoding":      string(enc),
+			"repo":          result.Payload.Repo,
+			"from":          payload.From.UTC().Format(time.RFC3339),
+			"to":            payload.To.UTC().Format(time.RFC3339),
+			"format":        payload.Format,
+			"language":      result.Payload.Language,
+			"isTestStandup": true,
 		}
+		addRenderings(values, result.Payload, payload.Renderings)
 		id, err := rdb.XAdd(ctx, &redis.XAddArgs{
 			Stream:     "scan:results",
 			MaxLen:     int64(cfg.RedisStreamMaxLen),
 			Approx:     true,
 			ID:         "*",
 			NoMkStream: false,
-			Values: map[string]any{
-				"payload":       string(testPayloadBytes),
-				"repo":          result.Payload.Repo,
-				"from":          payload.From.UTC().Format(time.RFC3339),
-				"to":            payload.To.UTC().Format(time.RFC3339),
-				"format":        payload.Format,
-				"isTestStandup": true,
-			},
+			Values:     values,
 		}).Result()
 		if err != nil {
-			return fmt.Errorf("publish test to scan:results: %w", err)
+			return "", fmt.Errorf("publish test to scan:results: %w", err)
 		}
 		log.Printf("[INFO] published test summary id=%s repo=%s/%s", id, payload.Owner, payload.Repo)
-		return nil
+		return id, nil
 	}
 
-	payloadBytes, err := json.Marshal(result.Payload)
+	payloadBytes, err := codec.EncodePayload(enc, result.Payload)
 	if err != nil {
-		return fmt.Errorf("marshal summary payload: %w", err)
+		return "", fmt.Errorf("encode summary payload as %s: %w", enc, err)
 	}
 
+	values := map[string]any{
+		"payload":  string(payloadBytes),
+		"encoding": string(enc),
+		"repo":     result.Payload.Repo,
+		"from":     payload.From.UTC().Format(time.RFC3339),
+		"to":       payload.To.UTC().Format(time.RFC3339),
+		"format":   payload.Format,
+		"language": result.Payload.Language,
+	}
+	addRenderings(values, result.Payload, payload.Renderings)
 	id, err := rdb.XAdd(ctx, &redis.XAddArgs{
 		Stream:     "scan:results",
 		MaxLen:     int64(cfg.RedisStreamMaxLen),
 		Approx:     true,
 		ID:         "*",
 		NoMkStream: false,
-		Values: map[string]any{
-			"payload": string(payloadBytes),
-			"repo":    result.Payload.Repo,
-			"from":    payload.From.UTC().Format(time.RFC3339),
-			"to":      payload.To.UTC().Format(time.RFC3339),
-			"format":  payload.Format,
-		},
+		Values:     values,
 	}).Result()
 	if err != nil {
-		return fmt.Errorf("publish to scan:results: %w", err)
+		return "", fmt.Errorf("publish to scan:results: %w", err)
 	}
 
 	log.Printf("[INFO] published summary id=%s repo=%s/%s", id, payload.Owner, payload.Repo)
-	return nil
+	return id, nil
+}
+
+// addRenderings adds each requested rendering of p to the result entry under
+// its own name. A rendering that fails is logged and left out rather than
+// holding back the payload.
+func addRenderings(values map[string]any, p ai.StandupPayload, names []string) {
+	kinds, _ := render.ParseKinds(names)
+	for _, kind := range kinds {
+		out, err := render.Render(p, kind)
+		if err != nil {
+			log.Printf("[WARN] render %s repo=%s: %v", kind, p.Repo, err)
+			continue
+		}
+		values[string(kind)] = out
+	}
 }
 
 func extractQueuePayload(msg redis.XMessage) (QueueMessage, error) {
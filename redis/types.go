@@ -1,16 +1,32 @@
 package redis
 
-import "time"
+import (
+	"time"
+
+	"github.com/urizennnn/autostandup-reposcanner/delivery"
+)
 
 type contextKey string
 
 type QueueMessage struct {
-	Owner          string    `json:"owner"`
-	IsTestStandup  bool      `json:"isTestStandup"`
-	Repo           string    `json:"repo"`
-	From           time.Time `json:"from"`
-	To             time.Time `json:"to"`
-	InstallationID int64     `json:"installation_id"`
-	Branch         string    `json:"branch"`
-	Format         string    `json:"format"`
+	Owner          string            `json:"owner"`
+	IsTestStandup  bool              `json:"isTestStandup"`
+	Repo           string            `json:"repo"`
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	InstallationID int64             `json:"installation_id"`
+	Branch         string            `json:"branch"`
+	Branches       []string          `json:"branches"`
+	AllBranches    bool              `json:"allBranches"`
+	IncludePaths   []string          `json:"includePaths"`
+	ExcludePaths   []string          `json:"excludePaths"`
+	IncludeDiffs   bool              `json:"includeDiffs"`
+	Format         string            `json:"format"`
+	Language       string            `json:"language"`
+	Mode           string            `json:"mode"`
+	Contributor    string            `json:"contributor"`
+	ForceRefresh   bool              `json:"forceRefresh"`
+	Renderings     []string          `json:"renderings"`
+	Encoding       string            `json:"encoding"`
+	Delivery       *delivery.Targets `json:"delivery"`
 }
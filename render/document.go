@@ -0,0 +1,303 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// document is the payload laid out once, so each output only decides how to
+// draw headings, paragraphs and lists. Text may carry the model's inline
+// Markdown (**bold**, `code`); each renderer converts or escapes it.
+type document struct {
+	title  string
+	window string
+	blocks []block
+}
+
+type block struct {
+	heading    string
+	paragraphs []string
+	items      []item
+}
+
+type item struct {
+	text string
+	url  string
+}
+
+func (b block) empty() bool {
+	return len(b.paragraphs) == 0 && len(b.items) == 0
+}
+
+// newDocument lays out whichever level the payload carries. Only one is
+// filled after pruning; custom formats render their "custom" object.
+func newDocument(p ai.StandupPayload) document {
+	d := document{title: p.Title}
+	if d.title == "" {
+		d.title = p.Repo
+	}
+	if p.Window.Since != "" || p.Window.Until != "" {
+		d.window = p.Window.Since + " – " + p.Window.Until
+	}
+
+	switch {
+	case p.Technical.Header != "" || len(p.Technical.WhatWorkedOn) > 0:
+		d.add(technicalBlocks(p.Technical)...)
+	case p.MildlyTechnical.Header != "" || len(p.MildlyTechnical.WhatWorkedOn) > 0:
+		d.add(summaryBlocks(p.MildlyTechnical)...)
+	case p.Layman.Header != "" || len(p.Layman.WhatWorkedOn) > 0:
+		d.add(summaryBlocks(p.Layman)...)
+	}
+	d.add(customBlocks(p.Custom)...)
+	d.add(sectionBlocks(p.Sections)...)
+	d.add(releaseBlock(p.Releases), componentBlock(p.Components), contributorBlock(p.Contributors), botBlock(p.Bots))
+	return d
+}
+
+func (d *document) add(blocks ...block) {
+	for _, b := range blocks {
+		if !b.empty() {
+			d.blocks = append(d.blocks, b)
+		}
+	}
+}
+
+func technicalBlocks(t ai.TechnicalLevel) []block {
+	out := []block{
+		{paragraphs: nonEmpty(t.Header)},
+		{heading: "What was worked on", items: texts(t.WhatWorkedOn)},
+	}
+	if t.CI != nil {
+		out = append(out, ciBlock(*t.CI))
+	}
+	if t.FilesChanged.Files > 0 {
+		out = append(out, filesBlock(t.FilesChanged))
+	}
+	out = append(out, block{heading: "Commits", items: texts(t.Commits)})
+	return out
+}
+
+func summaryBlocks(s ai.SummaryLevel) []block {
+	return []block{
+		{paragraphs: nonEmpty(s.Header)},
+		{heading: "What was worked on", items: texts(s.WhatWorkedOn)},
+		{heading: "Impact", paragraphs: nonEmpty(s.Impact)},
+		{heading: "Focus", paragraphs: nonEmpty(s.Focus)},
+	}
+}
+
+func ciBlock(ci ai.CIStatus) block {
+	line := fmt.Sprintf("CI is %s", ciState(ci.State))
+	if ci.Ref != "" {
+		line = fmt.Sprintf("CI on %s is %s", ci.Ref, ciState(ci.State))
+	}
+	if ci.RedSince != nil {
+		line += " since " + ci.RedSince.UTC().Format("Jan 2 15:04 MST")
+	}
+	b := block{heading: "CI", paragraphs: []string{line + "."}}
+	for _, f := range ci.Failing {
+		b.items = append(b.items, item{text: fmt.Sprintf("%s (%s)", f.Name, f.Conclusion), url: f.URL})
+	}
+	return b
+}
+
+func ciState(state string) string {
+	switch state {
+	case "success":
+		return "passing"
+	case "failure":
+		return "failing"
+	}
+	return state
+}
+
+func filesBlock(f ai.FilesChanged) block {
+	b := block{
+		heading:    "Files changed",
+		paragraphs: []string{fmt.Sprintf("%d files, +%d −%d", f.Files, f.Additions, f.Deletions)},
+	}
+	if len(f.Languages) > 0 {
+		b.paragraphs = append(b.paragraphs, "By language: "+shares(f.Languages))
+	}
+	if len(f.Categories) > 0 {
+		b.paragraphs = append(b.paragraphs, "By kind: "+shares(f.Categories))
+	}
+	return b
+}
+
+func shares(s []ai.ChangeShare) string {
+	parts := make([]string, len(s))
+	for i, c := range s {
+		parts[i] = fmt.Sprintf("%s %d (+%d −%d)", c.Name, c.Files, c.Additions, c.Deletions)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// customBlocks gives each top-level key of a custom format its own block,
+// in key order. Strings become paragraphs, lists become items and nested
+// objects become "key: value" items.
+func customBlocks(custom map[string]any) []block {
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []block
+	for _, k := range keys {
+		b := block{heading: humanize(k)}
+		switch v := custom[k].(type) {
+		case []any:
+			for _, e := range v {
+				b.items = append(b.items, item{text: scalar(e)})
+			}
+		case map[string]any:
+			sub := make([]string, 0, len(v))
+			for sk := range v {
+				sub = append(sub, sk)
+			}
+			sort.Strings(sub)
+			for _, sk := range sub {
+				b.items = append(b.items, item{text: humanize(sk) + ": " + scalar(v[sk])})
+			}
+		default:
+			b.paragraphs = nonEmpty(scalar(v))
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func sectionBlocks(sections []ai.ContributorSection) []block {
+	out := make([]block, 0, len(sections))
+	for _, s := range sections {
+		heading := "@" + strings.TrimPrefix(s.Handle, "@")
+		if s.Name != "" && !strings.EqualFold(s.Name, s.Handle) {
+			heading += " (" + s.Name + ")"
+		}
+		b := block{heading: heading, items: texts(s.WhatWorkedOn)}
+		if s.CommitCount > 0 {
+			b.paragraphs = []string{fmt.Sprintf("%d commits, %d files, +%d −%d",
+				s.CommitCount, s.FilesChanged.Files, s.FilesChanged.Additions, s.FilesChanged.Deletions)}
+		}
+		b.items = append(b.items, texts(s.Commits)...)
+		out = append(out, b)
+	}
+	return out
+}
+
+func releaseBlock(releases []ai.Release) block {
+	b := block{heading: "Releases"}
+	for _, r := range releases {
+		text := r.Tag
+		if r.Name != "" && r.Name != r.Tag {
+			text += " – " + r.Name
+		}
+		if r.Prerelease {
+			text += " (pre-release)"
+		}
+		if !r.At.IsZero() {
+			text += ", " + r.At.UTC().Format(time.DateOnly)
+		}
+		b.items = append(b.items, item{text: text, url: r.URL})
+	}
+	return b
+}
+
+func componentBlock(components []ai.Component) block {
+	b := block{heading: "Components"}
+	for _, c := range components {
+		b.items = append(b.items, item{text: fmt.Sprintf("%s: %d commits, +%d −%d",
+			c.Name, c.Commits, c.FilesChanged.Additions, c.FilesChanged.Deletions)})
+	}
+	return b
+}
+
+func contributorBlock(contributors []ai.Contributor) block {
+	b := block{heading: "Contributors"}
+	for _, c := range contributors {
+		text := c.Name
+		if c.Login != "" {
+			text += " (@" + c.Login + ")"
+		}
+		text += fmt.Sprintf(": %d commits", c.Commits)
+		if c.CoAuthored > 0 {
+			text += fmt.Sprintf(", %d co-authored", c.CoAuthored)
+		}
+		b.items = append(b.items, item{text: text})
+	}
+	return b
+}
+
+func botBlock(bots []ai.BotActivity) block {
+	b := block{heading: "Automated changes"}
+	for _, bot := range bots {
+		b.items = append(b.items, item{text: fmt.Sprintf("%s: %d commits", bot.Name, bot.Commits)})
+	}
+	return b
+}
+
+func texts(in []string) []item {
+	var out []item
+	for _, s := range in {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, item{text: s})
+		}
+	}
+	return out
+}
+
+func nonEmpty(s string) []string {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+func scalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = humanize(k) + ": " + scalar(t[k])
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// humanize turns a schema key such as "nextSteps" or "next_steps" into
+// "Next steps".
+func humanize(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_' || r == '-':
+			b.WriteByte(' ')
+		case i > 0 && r >= 'A' && r <= 'Z':
+			b.WriteByte(' ')
+			b.WriteRune(r + 'a' - 'A')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	s := b.String()
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"html"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// HTML renders p as an HTML fragment. All text is escaped; the only markup
+// is the fragment's own structure, <strong> and <code> from the model's
+// inline Markdown, and links to http(s) URLs.
+func HTML(p ai.StandupPayload) string {
+	d := newDocument(p)
+	var b strings.Builder
+	b.WriteString("<article>\n<h1>" + html.EscapeString(oneLine(d.title)) + "</h1>\n")
+	if d.window != "" {
+		b.WriteString("<p><em>" + html.EscapeString(d.window) + "</em></p>\n")
+	}
+	for _, bl := range d.blocks {
+		if bl.heading != "" {
+			b.WriteString("<h2>" + html.EscapeString(oneLine(bl.heading)) + "</h2>\n")
+		}
+		for _, para := range bl.paragraphs {
+			b.WriteString("<p>" + htmlInline(para) + "</p>\n")
+		}
+		if len(bl.items) == 0 {
+			continue
+		}
+		b.WriteString("<ul>\n")
+		for _, it := range bl.items {
+			text := htmlInline(oneLine(it.text))
+			if u := safeURL(it.url); u != "" {
+				text = `<a href="` + html.EscapeString(u) + `" rel="noopener noreferrer">` + text + "</a>"
+			}
+			b.WriteString("<li>" + text + "</li>\n")
+		}
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</article>\n")
+	return b.String()
+}
+
+// htmlInline escapes s, then turns **bold** and `code` into tags.
+func htmlInline(s string) string {
+	s = html.EscapeString(s)
+	s = codeRe.ReplaceAllString(s, "<code>$1</code>")
+	s = boldRe.ReplaceAllString(s, "<strong>$1</strong>")
+	s = strings.ReplaceAll(s, "\n", "<br>")
+	return s
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// Markdown renders p as GitHub flavored Markdown. The model's inline
+// formatting is kept as written; "<" is escaped so text cannot open raw
+// HTML.
+func Markdown(p ai.StandupPayload) string {
+	d := newDocument(p)
+	var b strings.Builder
+	b.WriteString("# " + markdownInline(oneLine(d.title)) + "\n")
+	if d.window != "" {
+		b.WriteString("\n_" + markdownInline(d.window) + "_\n")
+	}
+	for _, bl := range d.blocks {
+		if bl.heading != "" {
+			b.WriteString("\n## " + markdownInline(oneLine(bl.heading)) + "\n")
+		}
+		for _, para := range bl.paragraphs {
+			b.WriteString("\n" + markdownInline(para) + "\n")
+		}
+		if len(bl.items) > 0 {
+			b.WriteString("\n")
+		}
+		for _, it := range bl.items {
+			b.WriteString("- " + markdownLink(markdownInline(oneLine(it.text)), it.url) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// markdownLink links text to u when u is a safe URL.
+func markdownLink(text, u string) string {
+	if u = safeURL(u); u != "" {
+		return "[" + strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text) + "](" + u + ")"
+	}
+	return text
+}
+
+func markdownInline(s string) string {
+	return strings.ReplaceAll(s, "<", "&lt;")
+}
+
+// oneLine folds text onto a single line so it cannot end a list item or
+// heading early.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
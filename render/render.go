@@ -0,0 +1,73 @@
+// Package render turns a standup payload into ready-to-post text: GitHub
+// flavored Markdown, Slack Block Kit, Microsoft Teams Adaptive Cards and
+// sanitized HTML.
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+type Kind string
+
+const (
+	KindMarkdown Kind = "markdown"
+	KindSlack    Kind = "slack"
+	KindTeams    Kind = "teams"
+	KindHTML     Kind = "html"
+)
+
+// ParseKinds validates a job's requested renderings, dropping duplicates.
+func ParseKinds(names []string) ([]Kind, error) {
+	var out []Kind
+	seen := map[Kind]bool{}
+	for _, n := range names {
+		k := Kind(strings.ToLower(strings.TrimSpace(n)))
+		switch k {
+		case KindMarkdown, KindSlack, KindTeams, KindHTML:
+		default:
+			return nil, fmt.Errorf("unknown rendering %q", n)
+		}
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// Render returns p as kind. Slack and Teams renderings are JSON documents.
+func Render(p ai.StandupPayload, kind Kind) (string, error) {
+	switch kind {
+	case KindMarkdown:
+		return Markdown(p), nil
+	case KindSlack:
+		b, err := SlackBlocks(p)
+		return string(b), err
+	case KindTeams:
+		b, err := TeamsCard(p)
+		return string(b), err
+	case KindHTML:
+		return HTML(p), nil
+	}
+	return "", fmt.Errorf("unknown rendering %q", kind)
+}
+
+var (
+	boldRe = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	codeRe = regexp.MustCompile("`([^`\n]+)`")
+)
+
+// safeURL returns u if it is an absolute http(s) URL, so a link can never
+// carry a script or data URL into a rendering.
+func safeURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+	return parsed.String()
+}
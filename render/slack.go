@@ -0,0 +1,128 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+// Block Kit limits: header text, section text and blocks per message.
+const (
+	slackHeaderChars  = 150
+	slackSectionChars = 3000
+	slackMaxBlocks    = 50
+)
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackMessage is a message body accepted by chat.postMessage and incoming
+// webhooks. Text is the notification fallback.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// SlackBlocks renders p as a Slack Block Kit message. Long sections are split
+// to fit Slack's limits, and blocks past the 50th are replaced by a note.
+func SlackBlocks(p ai.StandupPayload) ([]byte, error) {
+	return json.Marshal(newSlackMessage(p))
+}
+
+func newSlackMessage(p ai.StandupPayload) slackMessage {
+	d := newDocument(p)
+	msg := slackMessage{Text: oneLine(d.title)}
+	msg.Blocks = append(msg.Blocks, slackBlock{
+		Type: "header",
+		Text: &slackText{Type: "plain_text", Text: clip(oneLine(d.title), slackHeaderChars), Emoji: true},
+	})
+	if d.window != "" {
+		msg.Blocks = append(msg.Blocks, slackBlock{
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: slackEscape(d.window)}},
+		})
+	}
+
+	for _, bl := range d.blocks {
+		var lines []string
+		if bl.heading != "" {
+			lines = append(lines, "*"+slackEscape(oneLine(bl.heading))+"*")
+		}
+		for _, para := range bl.paragraphs {
+			lines = append(lines, slackInline(para))
+		}
+		for _, it := range bl.items {
+			lines = append(lines, "• "+slackItem(it))
+		}
+		for _, text := range packLines(lines, slackSectionChars) {
+			msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}})
+		}
+	}
+
+	if len(msg.Blocks) > slackMaxBlocks {
+		msg.Blocks = append(msg.Blocks[:slackMaxBlocks-1], slackBlock{
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: "_Truncated to fit Slack's message limits._"}},
+		})
+	}
+	return msg
+}
+
+func slackItem(it item) string {
+	text := slackInline(oneLine(it.text))
+	if u := safeURL(it.url); u != "" {
+		return "<" + u + "|" + strings.ReplaceAll(text, "|", "/") + ">"
+	}
+	return text
+}
+
+// slackInline escapes text for mrkdwn and turns **bold** into *bold*.
+func slackInline(s string) string {
+	return boldRe.ReplaceAllString(slackEscape(s), "*$1*")
+}
+
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// packLines joins lines with newlines into chunks of at most max characters.
+// A single line longer than max is clipped.
+func packLines(lines []string, max int) []string {
+	var out []string
+	var cur strings.Builder
+	for _, l := range lines {
+		l = clip(l, max)
+		if cur.Len() > 0 && utf8.RuneCountInString(cur.String())+1+utf8.RuneCountInString(l) > max {
+			out = append(out, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(l)
+	}
+	if cur.Len() > 0 {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+// clip cuts s to at most n characters, ending in "…" when cut.
+func clip(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/urizennnn/autostandup-reposcanner/ai"
+)
+
+const adaptiveCardSchema = "http://adaptivecards.io/schemas/adaptive-card.json"
+
+type teamsElement struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Wrap     bool   `json:"wrap"`
+	Size     string `json:"size,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	IsSubtle bool   `json:"isSubtle,omitempty"`
+	Spacing  string `json:"spacing,omitempty"`
+}
+
+// adaptiveCard is an Adaptive Card 1.4. Teams webhooks expect it wrapped in
+// a message attachment of type application/vnd.microsoft.card.adaptive.
+type adaptiveCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+}
+
+// TeamsCard renders p as a Microsoft Teams Adaptive Card. TextBlocks accept
+// the Markdown subset Teams supports, so the model's bold text is kept.
+func TeamsCard(p ai.StandupPayload) ([]byte, error) {
+	return json.Marshal(newAdaptiveCard(p))
+}
+
+func newAdaptiveCard(p ai.StandupPayload) adaptiveCard {
+	d := newDocument(p)
+	card := adaptiveCard{Schema: adaptiveCardSchema, Type: "AdaptiveCard", Version: "1.4"}
+	card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: oneLine(d.title), Wrap: true, Size: "Large", Weight: "Bolder"})
+	if d.window != "" {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: d.window, Wrap: true, IsSubtle: true, Spacing: "None"})
+	}
+
+	for _, bl := range d.blocks {
+		if bl.heading != "" {
+			card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: oneLine(bl.heading), Wrap: true, Weight: "Bolder", Spacing: "Medium"})
+		}
+		for _, para := range bl.paragraphs {
+			card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: para, Wrap: true})
+		}
+		if len(bl.items) > 0 {
+			lines := make([]string, len(bl.items))
+			for i, it := range bl.items {
+				lines[i] = "- " + markdownLink(oneLine(it.text), it.url)
+			}
+			card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: strings.Join(lines, "\n"), Wrap: true})
+		}
+	}
+	return card
+}